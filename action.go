@@ -0,0 +1,170 @@
+package go_huawei
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Action is the maneuver of a step. Values the API adds later are kept as
+// they were received; use IsKnown or Canonical to tell them apart from the
+// constants below.
+type Action string
+
+// Maneuvers returned by the API.
+const (
+	End             Action = "end"
+	ForkLeft        Action = "fork-left"
+	ForkRight       Action = "fork-right"
+	RampRight       Action = "ramp-right"
+	RampLeft        Action = "ramp-left"
+	RoundaboutRight Action = "roundabout-right"
+	RoundaboutLeft  Action = "roundabout-left"
+	Straight        Action = "straight"
+	TurnLeft        Action = "turn-left"
+	TurnRight       Action = "turn-right"
+	TurnSlightLeft  Action = "turn-slight-left"
+	TurnSlightRight Action = "turn-slight-right"
+	TurnSharpLeft   Action = "turn-sharp-left"
+	TurnSharpRight  Action = "turn-sharp-right"
+	UTurnLeft       Action = "uturn-left"
+	UTurnRight      Action = "uturn-right"
+	Merge           Action = "merge"
+	Ferry           Action = "ferry"
+	FerryTrain      Action = "ferry-train"
+
+	// UnknownAction is what Canonical returns for values not listed above.
+	UnknownAction Action = "unknown"
+)
+
+// Direction is the side a maneuver goes to.
+type Direction string
+
+// Maneuver directions.
+const (
+	DirectionNone     Direction = ""
+	DirectionLeft     Direction = "left"
+	DirectionRight    Direction = "right"
+	DirectionStraight Direction = "straight"
+)
+
+// ActionCategory groups maneuvers of the same kind regardless of direction.
+type ActionCategory string
+
+// Maneuver categories.
+const (
+	ActionCategoryUnknown    ActionCategory = ""
+	ActionCategoryContinue   ActionCategory = "continue"
+	ActionCategoryTurn       ActionCategory = "turn"
+	ActionCategoryUTurn      ActionCategory = "uturn"
+	ActionCategoryFork       ActionCategory = "fork"
+	ActionCategoryRamp       ActionCategory = "ramp"
+	ActionCategoryMerge      ActionCategory = "merge"
+	ActionCategoryRoundabout ActionCategory = "roundabout"
+	ActionCategoryFerry      ActionCategory = "ferry"
+	ActionCategoryArrive     ActionCategory = "arrive"
+)
+
+type actionInfo struct {
+	direction Direction
+	category  ActionCategory
+}
+
+var actions = map[Action]actionInfo{
+	End:             {DirectionNone, ActionCategoryArrive},
+	ForkLeft:        {DirectionLeft, ActionCategoryFork},
+	ForkRight:       {DirectionRight, ActionCategoryFork},
+	RampRight:       {DirectionRight, ActionCategoryRamp},
+	RampLeft:        {DirectionLeft, ActionCategoryRamp},
+	RoundaboutRight: {DirectionRight, ActionCategoryRoundabout},
+	RoundaboutLeft:  {DirectionLeft, ActionCategoryRoundabout},
+	Straight:        {DirectionStraight, ActionCategoryContinue},
+	TurnLeft:        {DirectionLeft, ActionCategoryTurn},
+	TurnRight:       {DirectionRight, ActionCategoryTurn},
+	TurnSlightLeft:  {DirectionLeft, ActionCategoryTurn},
+	TurnSlightRight: {DirectionRight, ActionCategoryTurn},
+	TurnSharpLeft:   {DirectionLeft, ActionCategoryTurn},
+	TurnSharpRight:  {DirectionRight, ActionCategoryTurn},
+	UTurnLeft:       {DirectionLeft, ActionCategoryUTurn},
+	UTurnRight:      {DirectionRight, ActionCategoryUTurn},
+	Merge:           {DirectionNone, ActionCategoryMerge},
+	Ferry:           {DirectionNone, ActionCategoryFerry},
+	FerryTrain:      {DirectionNone, ActionCategoryFerry},
+}
+
+// Actions returns every known maneuver.
+func Actions() []Action {
+	return []Action{
+		Straight, TurnSlightLeft, TurnLeft, TurnSharpLeft, UTurnLeft,
+		TurnSlightRight, TurnRight, TurnSharpRight, UTurnRight,
+		ForkLeft, ForkRight, RampLeft, RampRight, Merge,
+		RoundaboutLeft, RoundaboutRight, Ferry, FerryTrain, End,
+	}
+}
+
+// ParseAction returns the action named by s. Case, surrounding spaces and
+// underscores in place of hyphens are tolerated for known actions, so
+// "TURN_LEFT" is TurnLeft; other values are returned unchanged.
+func ParseAction(s string) Action {
+	normalized := Action(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "_", "-"))
+	if normalized == "u-turn-left" || normalized == "u-turn-right" {
+		normalized = "uturn-" + normalized[len("u-turn-"):]
+	}
+	if normalized.IsKnown() {
+		return normalized
+	}
+
+	return Action(s)
+}
+
+// IsKnown reports whether a is one of the documented maneuvers.
+func (a Action) IsKnown() bool {
+	_, ok := actions[a]
+	return ok
+}
+
+// Canonical returns a if it is known and UnknownAction otherwise, so that a
+// switch over the constants can handle new values explicitly.
+func (a Action) Canonical() Action {
+	if a.IsKnown() {
+		return a
+	}
+
+	return UnknownAction
+}
+
+// Direction returns the side the maneuver goes to; DirectionNone for
+// maneuvers without one, such as End or Ferry, and for unknown values.
+func (a Action) Direction() Direction {
+	return actions[a].direction
+}
+
+// Category returns the kind of the maneuver; ActionCategoryUnknown for
+// unknown values.
+func (a Action) Category() ActionCategory {
+	return actions[a].category
+}
+
+// UnmarshalJSON accepts any JSON scalar. Strings go through ParseAction;
+// numbers and booleans are kept as their text and null leaves the action
+// unchanged, as it does for other types.
+func (a *Action) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*a = ParseAction(s)
+	case len(data) > 0 && (data[0] == '{' || data[0] == '['):
+		return fmt.Errorf("map-kit: action must be a scalar, got %.20s", data)
+	default:
+		*a = Action(data)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package go_huawei
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestActionUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want Action
+	}{
+		{`"turn-left"`, TurnLeft},
+		{`" TURN_SLIGHT_RIGHT "`, TurnSlightRight},
+		{`"U-Turn-Left"`, UTurnLeft},
+		{`"hover-left"`, "hover-left"},
+		{`"Hover_Left"`, "Hover_Left"},
+		{`""`, ""},
+		{`7`, "7"},
+		{` -1.5e3 `, "-1.5e3"},
+		{`true`, "true"},
+	}
+
+	for _, test := range tests {
+		var a Action
+		if err := json.Unmarshal([]byte(test.data), &a); err != nil || a != test.want {
+			t.Errorf("Unmarshal(%s) = %q, %v; want %q", test.data, a, err, test.want)
+		}
+	}
+
+	var step struct {
+		Action Action `json:"action"`
+	}
+	step.Action = Merge
+	if err := json.Unmarshal([]byte(`{"action":null}`), &step); err != nil || step.Action != Merge {
+		t.Errorf("null = %q, %v; want the action unchanged", step.Action, err)
+	}
+
+	for _, data := range []string{`{"name":"left"}`, `["left"]`, `"unterminated`} {
+		var a Action
+		if err := json.Unmarshal([]byte(data), &a); err == nil {
+			t.Errorf("Unmarshal(%s) = %q, want an error", data, a)
+		}
+	}
+}
+
+func TestActionClassification(t *testing.T) {
+	if len(Actions()) != len(actions) {
+		t.Errorf("Actions() lists %d maneuvers, %d are known", len(Actions()), len(actions))
+	}
+	for _, a := range Actions() {
+		if !a.IsKnown() || a.Canonical() != a || a.Category() == ActionCategoryUnknown {
+			t.Errorf("%q is not fully classified", a)
+		}
+	}
+
+	tests := []struct {
+		action    Action
+		direction Direction
+		category  ActionCategory
+	}{
+		{TurnSharpLeft, DirectionLeft, ActionCategoryTurn},
+		{RoundaboutRight, DirectionRight, ActionCategoryRoundabout},
+		{Straight, DirectionStraight, ActionCategoryContinue},
+		{FerryTrain, DirectionNone, ActionCategoryFerry},
+		{End, DirectionNone, ActionCategoryArrive},
+		{"hover-left", DirectionNone, ActionCategoryUnknown},
+	}
+
+	for _, test := range tests {
+		if d, c := test.action.Direction(), test.action.Category(); d != test.direction || c != test.category {
+			t.Errorf("%q: direction %q, category %q; want %q, %q", test.action, d, c, test.direction, test.category)
+		}
+	}
+
+	if got := Action("hover-left").Canonical(); got != UnknownAction {
+		t.Errorf("Canonical of an unknown action = %q", got)
+	}
+	if UnknownAction.IsKnown() {
+		t.Error("UnknownAction is known")
+	}
+}
@@ -0,0 +1,191 @@
+package go_huawei
+
+import (
+	"math"
+	"sort"
+
+	"github.com/stremovskyy/go-huawei/internal/mercator"
+)
+
+const maxFitZoom = 20
+
+// NewCoordinateBounds returns the smallest bounds containing all coords, e.g. of
+// Path.Overview(). When the points are closer across the antimeridian the
+// bounds cross it, i.e. Southwest.Lng > Northeast.Lng. It returns empty bounds
+// for no coords.
+func NewCoordinateBounds(coords []Coordinate) CoordinateBounds {
+	if len(coords) == 0 {
+		return CoordinateBounds{}
+	}
+
+	south, north := coords[0].Lat, coords[0].Lat
+	lngs := make([]float64, 0, len(coords))
+	for _, c := range coords {
+		south = math.Min(south, c.Lat)
+		north = math.Max(north, c.Lat)
+		lngs = append(lngs, normalizeLng(c.Lng))
+	}
+	sort.Float64s(lngs)
+
+	// The bounds span everything but the largest gap between neighbouring
+	// longitudes, including the gap across the antimeridian.
+	west, east := lngs[0], lngs[len(lngs)-1]
+	largestGap := lngs[0] + 360 - lngs[len(lngs)-1]
+	for i := 1; i < len(lngs); i++ {
+		if gap := lngs[i] - lngs[i-1]; gap > largestGap {
+			largestGap = gap
+			west, east = lngs[i], lngs[i-1]
+		}
+	}
+
+	coordType := coords[0].CoordType
+	return CoordinateBounds{
+		Southwest: Coordinate{Lat: south, Lng: west, CoordType: coordType},
+		Northeast: Coordinate{Lat: north, Lng: east, CoordType: coordType},
+	}
+}
+
+// IsEmpty reports whether the bounds are the zero value, i.e. both corners are
+// at (0,0). Bounds around (0,0) alone are therefore empty.
+func (b CoordinateBounds) IsEmpty() bool {
+	return b.Southwest == Coordinate{} && b.Northeast == Coordinate{}
+}
+
+// CrossesAntimeridian reports whether the bounds cross the 180th meridian.
+func (b CoordinateBounds) CrossesAntimeridian() bool {
+	return b.Southwest.Lng > b.Northeast.Lng
+}
+
+// Span returns the height and width of the bounds, in degrees.
+func (b CoordinateBounds) Span() (lat, lng float64) {
+	return b.Northeast.Lat - b.Southwest.Lat, b.lngSpan()
+}
+
+func (b CoordinateBounds) lngSpan() float64 {
+	span := b.Northeast.Lng - b.Southwest.Lng
+	if span < 0 {
+		span += 360
+	}
+
+	return span
+}
+
+// Center returns the centre of the bounds.
+func (b CoordinateBounds) Center() Coordinate {
+	return Coordinate{
+		Lat:       (b.Southwest.Lat + b.Northeast.Lat) / 2,
+		Lng:       normalizeLng(b.Southwest.Lng + b.lngSpan()/2),
+		CoordType: b.Southwest.CoordType,
+	}
+}
+
+// Contains reports whether c lies within the bounds, edges included.
+func (b CoordinateBounds) Contains(c Coordinate) bool {
+	if c.Lat < b.Southwest.Lat || c.Lat > b.Northeast.Lat {
+		return false
+	}
+
+	return lngDistanceEast(b.Southwest.Lng, c.Lng) <= b.lngSpan()
+}
+
+// Intersects reports whether the bounds share at least one point with other.
+func (b CoordinateBounds) Intersects(other CoordinateBounds) bool {
+	if b.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+
+	if other.Southwest.Lat > b.Northeast.Lat || other.Northeast.Lat < b.Southwest.Lat {
+		return false
+	}
+
+	return lngDistanceEast(b.Southwest.Lng, other.Southwest.Lng) <= b.lngSpan() ||
+		lngDistanceEast(other.Southwest.Lng, b.Southwest.Lng) <= other.lngSpan()
+}
+
+// Extend returns the smallest bounds containing both b and c. Extending empty
+// bounds yields bounds around c alone.
+func (b CoordinateBounds) Extend(c Coordinate) CoordinateBounds {
+	return b.Union(CoordinateBounds{Southwest: c, Northeast: c})
+}
+
+// Union returns the smallest bounds containing both b and other.
+func (b CoordinateBounds) Union(other CoordinateBounds) CoordinateBounds {
+	switch {
+	case b.IsEmpty():
+		return other
+	case other.IsEmpty():
+		return b
+	}
+
+	// Of the two arcs starting at either west edge and covering both ranges,
+	// the shorter one is the union.
+	west, east, span := coveringArc(b, other)
+	if altWest, altEast, altSpan := coveringArc(other, b); altSpan < span {
+		west, east, span = altWest, altEast, altSpan
+	}
+
+	if span >= 360 {
+		west, east = -180, 180
+	}
+
+	return CoordinateBounds{
+		Southwest: Coordinate{Lat: math.Min(b.Southwest.Lat, other.Southwest.Lat), Lng: west, CoordType: b.Southwest.CoordType},
+		Northeast: Coordinate{Lat: math.Max(b.Northeast.Lat, other.Northeast.Lat), Lng: east, CoordType: b.Northeast.CoordType},
+	}
+}
+
+// coveringArc returns the arc of longitudes starting at the west edge of a and
+// extending east until it covers b.
+func coveringArc(a, b CoordinateBounds) (west, east, span float64) {
+	viaB := lngDistanceEast(a.Southwest.Lng, b.Southwest.Lng) + b.lngSpan()
+	if a.lngSpan() >= viaB {
+		return a.Southwest.Lng, a.Northeast.Lng, a.lngSpan()
+	}
+
+	return a.Southwest.Lng, b.Northeast.Lng, viaB
+}
+
+// Pad returns the bounds grown by the given distance, in metres, on every side.
+// Empty bounds stay empty.
+func (b CoordinateBounds) Pad(meters float64) CoordinateBounds {
+	if b.IsEmpty() {
+		return b
+	}
+
+	dLat := toDegrees(meters / EarthRadius)
+	south := math.Max(-90, b.Southwest.Lat-dLat)
+	north := math.Min(90, b.Northeast.Lat+dLat)
+
+	// Grow longitudes by the distance at the latitude where meridians are
+	// closest so that the padding is at least the requested distance.
+	widest := math.Max(math.Abs(south), math.Abs(north))
+	west, east := -180.0, 180.0
+	if widest < 90 {
+		dLng := dLat / math.Cos(toRadians(widest))
+		if b.lngSpan()+2*dLng < 360 {
+			west = normalizeLng(b.Southwest.Lng - dLng)
+			east = normalizeLng(b.Northeast.Lng + dLng)
+		}
+	}
+
+	return CoordinateBounds{
+		Southwest: Coordinate{Lat: south, Lng: west, CoordType: b.Southwest.CoordType},
+		Northeast: Coordinate{Lat: north, Lng: east, CoordType: b.Northeast.CoordType},
+	}
+}
+
+// FitZoom returns the largest Web Mercator zoom level, up to 20, at which the
+// bounds fit into a viewport of the given size in pixels.
+func (b CoordinateBounds) FitZoom(width, height int) int {
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	zoom := mercator.FitZoom(b.Southwest.Lat, b.Northeast.Lat, b.lngSpan(), float64(width), float64(height), maxFitZoom)
+	return int(math.Max(0, math.Floor(zoom)))
+}
+
+// lngDistanceEast returns how far east of from the longitude to lies, in [0, 360).
+func lngDistanceEast(from, to float64) float64 {
+	return math.Mod(math.Mod(to-from, 360)+360, 360)
+}
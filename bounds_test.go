@@ -0,0 +1,140 @@
+package go_huawei
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtendFromEmpty(t *testing.T) {
+	var b CoordinateBounds
+	if !b.IsEmpty() {
+		t.Fatal("zero bounds are not empty")
+	}
+
+	b = b.Extend(Coordinate{Lat: -1, Lng: 2}).Extend(Coordinate{Lat: 5, Lng: 5})
+	want := CoordinateBounds{Southwest: Coordinate{Lat: -1, Lng: 2}, Northeast: Coordinate{Lat: 5, Lng: 5}}
+	if b != want {
+		t.Errorf("Extend = %+v, want %+v", b, want)
+	}
+
+	// Bounds around (0,0) alone are the zero value.
+	if origin := (CoordinateBounds{}).Extend(Coordinate{}); !origin.IsEmpty() || origin != (CoordinateBounds{}) {
+		t.Errorf("bounds around (0,0) = %+v, want empty", origin)
+	}
+	if b := NewCoordinateBounds([]Coordinate{{}, {Lat: 1, Lng: 1}}); b.IsEmpty() || !b.Contains(Coordinate{}) {
+		t.Errorf("bounds from (0,0) = %+v, want them to contain it", b)
+	}
+}
+
+func TestNewCoordinateBoundsAntimeridian(t *testing.T) {
+	b := NewCoordinateBounds([]Coordinate{{Lat: -17, Lng: 178}, {Lat: -16, Lng: -179}, {Lat: -18, Lng: 179.5}})
+
+	if b.Southwest.Lng != 178 || b.Northeast.Lng != -179 {
+		t.Fatalf("bounds = %+v, want 178 to -179", b)
+	}
+	if !b.CrossesAntimeridian() {
+		t.Error("bounds do not cross the antimeridian")
+	}
+	if _, lng := b.Span(); lng != 3 {
+		t.Errorf("lng span = %v, want 3", lng)
+	}
+	if c := b.Center(); c.Lng != 179.5 || c.Lat != -17 {
+		t.Errorf("Center = %+v, want -17,179.5", c)
+	}
+
+	for _, c := range []Coordinate{{Lat: -17, Lng: 180}, {Lat: -17, Lng: -180}, {Lat: -16, Lng: -179}} {
+		if !b.Contains(c) {
+			t.Errorf("%v is not contained", c)
+		}
+	}
+	for _, c := range []Coordinate{{Lat: -17, Lng: 0}, {Lat: -15, Lng: 179}} {
+		if b.Contains(c) {
+			t.Errorf("%v is contained", c)
+		}
+	}
+}
+
+func TestUnion(t *testing.T) {
+	fiji := CoordinateBounds{Southwest: Coordinate{Lat: -19, Lng: 177}, Northeast: Coordinate{Lat: -16, Lng: -179}}
+	samoa := CoordinateBounds{Southwest: Coordinate{Lat: -14, Lng: -172}, Northeast: Coordinate{Lat: -13, Lng: -171}}
+
+	u := fiji.Union(samoa)
+	if u.Southwest != (Coordinate{Lat: -19, Lng: 177}) || u.Northeast != (Coordinate{Lat: -13, Lng: -171}) {
+		t.Errorf("Union = %+v, want -19,177 to -13,-171", u)
+	}
+	if u != samoa.Union(fiji) {
+		t.Error("Union is not symmetric")
+	}
+	if !u.Intersects(fiji) || !u.Intersects(samoa) || fiji.Intersects(samoa) {
+		t.Error("Intersects disagrees with the union")
+	}
+
+	if got := (CoordinateBounds{}).Union(fiji); got != fiji {
+		t.Errorf("empty ∪ fiji = %+v", got)
+	}
+	if (CoordinateBounds{}).Intersects(fiji) {
+		t.Error("empty bounds intersect")
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got := (CoordinateBounds{}).Pad(1000); !got.IsEmpty() {
+		t.Errorf("padded empty bounds = %+v", got)
+	}
+
+	b := NewCoordinateBounds([]Coordinate{{Lat: 0.5}, {Lat: -0.5}}).Pad(equatorDegree)
+	if b.Southwest.Lat > -1 || b.Northeast.Lat < 1 || b.Southwest.Lng > -1 || b.Northeast.Lng < 1 {
+		t.Errorf("Pad = %+v, want at least a degree on every side", b)
+	}
+
+	if polar := NewCoordinateBounds([]Coordinate{{Lat: 89.5, Lng: 10}}).Pad(2 * equatorDegree); polar.Northeast.Lat != 90 || polar.Southwest.Lng != -180 || polar.Northeast.Lng != 180 {
+		t.Errorf("Pad near the pole = %+v, want all longitudes up to 90", polar)
+	}
+}
+
+func TestFitZoom(t *testing.T) {
+	tests := []struct {
+		name          string
+		bounds        CoordinateBounds
+		width, height int
+		want          int
+	}{
+		{"one degree", NewCoordinateBounds([]Coordinate{{}, {Lat: 1, Lng: 1}}), 256, 256, 8},
+		{"wider viewport", NewCoordinateBounds([]Coordinate{{}, {Lat: 1, Lng: 1}}), 1024, 1024, 10},
+		{"across the antimeridian", NewCoordinateBounds([]Coordinate{{Lng: 179.5}, {Lat: 1, Lng: -179.5}}), 256, 256, 8},
+		{"whole world", NewCoordinateBounds([]Coordinate{{Lat: -80, Lng: -180}, {Lat: 80, Lng: 180}}), 256, 256, 0},
+		{"single point", NewCoordinateBounds([]Coordinate{{Lat: 50, Lng: 36}}), 256, 256, maxFitZoom},
+		{"no viewport", NewCoordinateBounds([]Coordinate{{}, {Lat: 1, Lng: 1}}), 0, 256, 0},
+	}
+
+	for _, test := range tests {
+		if got := test.bounds.FitZoom(test.width, test.height); got != test.want {
+			t.Errorf("%s: FitZoom = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBoundsJSON(t *testing.T) {
+	b := NewCoordinateBounds([]Coordinate{{Lat: -19, Lng: 177}, {Lat: -16, Lng: -179}})
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded CoordinateBounds
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded != b {
+		t.Errorf("decoded %s as %+v, %v; want %+v", data, decoded, err, b)
+	}
+
+	var missing Route
+	if err := json.Unmarshal([]byte(`{"bounds":null}`), &missing); err != nil || !missing.Bounds.IsEmpty() {
+		t.Errorf("null bounds = %+v, %v; want empty", missing.Bounds, err)
+	}
+}
+
+func TestNewCoordinateBoundsExact(t *testing.T) {
+	b := NewCoordinateBounds([]Coordinate{{Lat: 50, Lng: 30}, {Lat: 51.1, Lng: 31.1}, {Lat: 50.5, Lng: 180}})
+	if b.Southwest.Lng != 30 || b.Northeast.Lng != -180 || b.Northeast.Lat != 51.1 {
+		t.Errorf("bounds = %+v, want the vertex longitudes unrounded", b)
+	}
+}
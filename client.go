@@ -9,9 +9,11 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"golang.org/x/time/rate"
 
+	"github.com/stremovskyy/go-huawei/coordsys"
 	"github.com/stremovskyy/go-huawei/internal"
 	"github.com/stremovskyy/go-huawei/metrics"
 )
@@ -23,6 +25,11 @@ type Client struct {
 	requestsPerSecond int
 	rateLimiter       *rate.Limiter
 	metricReporter    metrics.Reporter
+	logger            Logger
+	logLevel          LogLevel
+	errorLogLevel     LogLevel
+	logBodyLimit      int
+	coordType         coordsys.System
 }
 
 // ClientOption is the type of constructor options for NewClient(...).
@@ -34,6 +41,8 @@ func NewClient(options ...ClientOption) (*Client, error) {
 	c := &Client{
 		requestsPerSecond: defaultRequestsPerSecond,
 		metricReporter:    metrics.NoOpReporter{},
+		logLevel:          defaultLogLevel,
+		errorLogLevel:     defaultErrorLogLevel,
 	}
 
 	err := WithHTTPClient(&http.Client{})(c)
@@ -193,9 +202,13 @@ func (c *Client) do(ctx context.Context, req *http.Request) ([]byte, error) {
 		client = http.DefaultClient
 	}
 
+	start := time.Now()
+	c.logRequestStart(req)
+
 	resp, err := client.Do(req.WithContext(ctx))
 
 	if err != nil {
+		c.logRequestEnd(req, start, 0, nil, err)
 		return nil, err
 	}
 
@@ -207,6 +220,7 @@ func (c *Client) do(ctx context.Context, req *http.Request) ([]byte, error) {
 	case "gzip":
 		reader, err = gzip.NewReader(resp.Body)
 		if err != nil {
+			c.logRequestEnd(req, start, resp.StatusCode, nil, err)
 			return nil, err
 		}
 
@@ -215,7 +229,10 @@ func (c *Client) do(ctx context.Context, req *http.Request) ([]byte, error) {
 		reader = resp.Body
 	}
 
-	return ioutil.ReadAll(reader)
+	body, err := ioutil.ReadAll(reader)
+	c.logRequestEnd(req, start, resp.StatusCode, body, err)
+
+	return body, err
 }
 
 func (c *Client) getJSON(ctx context.Context, config *apiConfig, apiReq *DirectionsRequest, resp interface{}, routeService RouteService) error {
@@ -0,0 +1,107 @@
+package go_huawei
+
+import (
+	"fmt"
+
+	"github.com/stremovskyy/go-huawei/coordsys"
+)
+
+// apiCoordType is the coordinate system the Map Kit API works in. GCJ-02 equals
+// WGS-84 outside mainland China, so the API is GCJ-02 everywhere.
+const apiCoordType = coordsys.GCJ02
+
+// WithCoordType declares the coordinate system of the caller. Request
+// coordinates without a CoordType are taken to be in this system and converted
+// for the API, and response coordinates are converted back to it. By default no
+// conversion takes place.
+func WithCoordType(system coordsys.System) ClientOption {
+	return func(c *Client) error {
+		if !system.Valid() {
+			return fmt.Errorf("map-kit: unknown coordinate system '%s'", system)
+		}
+
+		c.coordType = system
+		return nil
+	}
+}
+
+// To returns the coordinate converted to the given system. A coordinate
+// without a CoordType is taken to be in GCJ-02, the system of the API, as
+// response coordinates are.
+func (c *Coordinate) To(system coordsys.System) Coordinate {
+	from := c.CoordType
+	if from == "" {
+		from = apiCoordType
+	}
+
+	lat, lng := coordsys.Convert(c.Lat, c.Lng, from, system)
+	return Coordinate{Lat: lat, Lng: lng, CoordType: system}
+}
+
+// To returns the bounds with both corners converted to the given system.
+func (b CoordinateBounds) To(system coordsys.System) CoordinateBounds {
+	if b.IsEmpty() {
+		return b
+	}
+
+	return CoordinateBounds{
+		Southwest: b.Southwest.To(system),
+		Northeast: b.Northeast.To(system),
+	}
+}
+
+// toAPICoordinates returns a copy of the request with its coordinates converted
+// from the caller's system to the system of the API.
+func (r *DirectionsRequest) toAPICoordinates(callerType coordsys.System) *DirectionsRequest {
+	converted := *r
+	converted.Origin = toAPICoordinate(r.Origin, callerType)
+	converted.Destination = toAPICoordinate(r.Destination, callerType)
+
+	if r.Waypoints != nil {
+		converted.Waypoints = make([]*Coordinate, len(r.Waypoints))
+		for i, waypoint := range r.Waypoints {
+			converted.Waypoints[i] = toAPICoordinate(waypoint, callerType)
+		}
+	}
+
+	return &converted
+}
+
+func toAPICoordinate(c *Coordinate, callerType coordsys.System) *Coordinate {
+	if c == nil {
+		return nil
+	}
+
+	tagged := *c
+	if tagged.CoordType == "" {
+		tagged.CoordType = callerType
+	}
+
+	converted := tagged.To(apiCoordType)
+	return &converted
+}
+
+// convertRoutes converts every coordinate of the routes, in place, from the
+// system of the API to the given system.
+func convertRoutes(routes []Route, system coordsys.System) {
+	for i := range routes {
+		route := &routes[i]
+		route.Bounds = route.Bounds.To(system)
+
+		for j := range route.Paths {
+			path := &route.Paths[j]
+			path.StartLocation = path.StartLocation.To(system)
+			path.EndLocation = path.EndLocation.To(system)
+
+			for k := range path.Steps {
+				step := &path.Steps[k]
+				step.StartLocation = step.StartLocation.To(system)
+				step.EndLocation = step.EndLocation.To(system)
+
+				for l := range step.Polyline {
+					step.Polyline[l] = step.Polyline[l].To(system)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package go_huawei_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/coordsys"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+// echoRoute answers with a route through the requested points, as the API
+// would: in its own system.
+func echoRoute(req *huaweitest.Request) huaweitest.Response {
+	d := req.Directions
+	polyline := []go_huawei.Coordinate{*d.Origin}
+	for _, waypoint := range d.Waypoints {
+		polyline = append(polyline, *waypoint)
+	}
+	polyline = append(polyline, *d.Destination)
+
+	return huaweitest.Response{Routes: []go_huawei.Route{{
+		Bounds: go_huawei.NewCoordinateBounds(polyline),
+		Paths: []go_huawei.Path{{
+			StartLocation: *d.Origin,
+			EndLocation:   *d.Destination,
+			Steps: []go_huawei.Step{{
+				StartLocation: *d.Origin,
+				EndLocation:   *d.Destination,
+				Polyline:      polyline,
+			}},
+		}},
+	}}}
+}
+
+// near reports whether the coordinate lies within about a metre of lat,lng.
+func near(c go_huawei.Coordinate, lat, lng float64) bool {
+	return math.Abs(c.Lat-lat) < 1e-5 && math.Abs(c.Lng-lng) < 1e-5
+}
+
+func TestDirectionsCoordType(t *testing.T) {
+	server := huaweitest.NewServer(huaweitest.WithResponder(echoRoute))
+	defer server.Close()
+
+	client, err := go_huawei.NewClient(go_huawei.WithAPIKey("key"), go_huawei.WithBaseURL(server.URL), go_huawei.WithCoordType(coordsys.WGS84))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Beijing, in WGS-84 except for the waypoint, which is tagged BD-09.
+	origin := go_huawei.Coordinate{Lat: 39.9042, Lng: 116.4074}
+	destination := go_huawei.Coordinate{Lat: 39.9561, Lng: 116.3103}
+	waypoint := go_huawei.Coordinate{Lat: 39.9300, Lng: 116.3900, CoordType: coordsys.BD09}
+
+	routes, err := client.Directions(context.Background(), &go_huawei.DirectionsRequest{
+		Origin:      &origin,
+		Destination: &destination,
+		Waypoints:   []*go_huawei.Coordinate{&waypoint},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The API receives GCJ-02.
+	sent := server.Requests()[0].Directions
+	for _, check := range []struct {
+		name     string
+		got      *go_huawei.Coordinate
+		lat, lng float64
+		from     coordsys.System
+	}{
+		{"origin", sent.Origin, origin.Lat, origin.Lng, coordsys.WGS84},
+		{"destination", sent.Destination, destination.Lat, destination.Lng, coordsys.WGS84},
+		{"waypoint", sent.Waypoints[0], waypoint.Lat, waypoint.Lng, coordsys.BD09},
+	} {
+		lat, lng := coordsys.Convert(check.lat, check.lng, check.from, coordsys.GCJ02)
+		if !near(*check.got, lat, lng) {
+			t.Errorf("sent %s = %v, want GCJ-02 %.6f,%.6f", check.name, check.got, lat, lng)
+		}
+	}
+	if near(*sent.Origin, origin.Lat, origin.Lng) {
+		t.Error("origin was sent unconverted")
+	}
+	if origin.CoordType != "" || waypoint.CoordType != coordsys.BD09 {
+		t.Error("request coordinates were modified")
+	}
+
+	// The response comes back in WGS-84, waypoint included. The corners of
+	// the bounds are converted as points of their own.
+	wLat, wLng := coordsys.Convert(waypoint.Lat, waypoint.Lng, coordsys.BD09, coordsys.WGS84)
+	swLat, swLng := coordsys.Convert(sent.Origin.Lat, sent.Destination.Lng, coordsys.GCJ02, coordsys.WGS84)
+	neLat, neLng := coordsys.Convert(sent.Destination.Lat, sent.Origin.Lng, coordsys.GCJ02, coordsys.WGS84)
+	route := routes[0]
+	path := route.Paths[0]
+	step := path.Steps[0]
+	for _, check := range []struct {
+		name     string
+		got      go_huawei.Coordinate
+		lat, lng float64
+	}{
+		{"path start", path.StartLocation, origin.Lat, origin.Lng},
+		{"path end", path.EndLocation, destination.Lat, destination.Lng},
+		{"step start", step.StartLocation, origin.Lat, origin.Lng},
+		{"step end", step.EndLocation, destination.Lat, destination.Lng},
+		{"polyline start", step.Polyline[0], origin.Lat, origin.Lng},
+		{"polyline waypoint", step.Polyline[1], wLat, wLng},
+		{"polyline end", step.Polyline[2], destination.Lat, destination.Lng},
+		{"bounds southwest", route.Bounds.Southwest, swLat, swLng},
+		{"bounds northeast", route.Bounds.Northeast, neLat, neLng},
+	} {
+		if !near(check.got, check.lat, check.lng) || check.got.CoordType != coordsys.WGS84 {
+			t.Errorf("%s = %v (%s), want WGS-84 %.6f,%.6f", check.name, &check.got, check.got.CoordType, check.lat, check.lng)
+		}
+	}
+}
+
+func TestDirectionsWithoutCoordType(t *testing.T) {
+	server := huaweitest.NewServer(huaweitest.WithResponder(echoRoute))
+	defer server.Close()
+
+	client, err := go_huawei.NewClient(go_huawei.WithAPIKey("key"), go_huawei.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origin := go_huawei.Coordinate{Lat: 39.9042, Lng: 116.4074}
+	destination := go_huawei.Coordinate{Lat: 39.9561, Lng: 116.3103}
+	routes, err := client.Directions(context.Background(), &go_huawei.DirectionsRequest{Origin: &origin, Destination: &destination})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent := server.Requests()[0].Directions.Origin; *sent != origin {
+		t.Errorf("sent origin = %v, want it unchanged", sent)
+	}
+	if start := routes[0].Paths[0].StartLocation; start != origin {
+		t.Errorf("path start = %v, want it unchanged", &start)
+	}
+}
+
+func TestCoordinateTo(t *testing.T) {
+	// Without a CoordType the coordinate is taken to be in GCJ-02.
+	untagged := go_huawei.Coordinate{Lat: 39.9042, Lng: 116.4074}
+	tagged := untagged
+	tagged.CoordType = coordsys.GCJ02
+	if a, b := untagged.To(coordsys.WGS84), tagged.To(coordsys.WGS84); a != b {
+		t.Errorf("untagged converts to %v, GCJ-02 to %v", &a, &b)
+	}
+
+	// Outside China the systems agree.
+	kyiv := go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234, CoordType: coordsys.WGS84}
+	if got := kyiv.To(coordsys.GCJ02); got.Lat != kyiv.Lat || got.Lng != kyiv.Lng || got.CoordType != coordsys.GCJ02 {
+		t.Errorf("Kyiv in GCJ-02 = %v (%s)", &got, got.CoordType)
+	}
+}
@@ -1,46 +1,249 @@
 package go_huawei
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/stremovskyy/go-huawei/coordsys"
 )
 
+// defaultCoordinatePrecision is the number of decimal places Coordinate.String
+// uses.
+const defaultCoordinatePrecision = 6
+
 type Coordinate struct {
 	Lng float64 `json:"lng"`
 	Lat float64 `json:"lat"`
+
+	// CoordType is the coordinate system of the point. Empty means the native
+	// system of the API: GCJ-02 inside mainland China, WGS-84 elsewhere.
+	CoordType coordsys.System `json:"-"`
 }
 
+// String returns the coordinate as "lat,lng" with six decimal places. Use
+// Format for another precision.
 func (c *Coordinate) String() string {
+	return c.Format(defaultCoordinatePrecision)
+}
+
+// Format returns the coordinate as "lat,lng" with the given number of decimal
+// places.
+func (c *Coordinate) Format(precision int) string {
 	if c == nil {
 		return ""
 	}
 
-	return fmt.Sprintf("%f,%f", c.Lat, c.Lng)
+	return strconv.FormatFloat(c.Lat, 'f', precision, 64) + "," + strconv.FormatFloat(c.Lng, 'f', precision, 64)
 }
 
 func (c *Coordinate) isEmpty() bool {
 	return c == nil
 }
 
+// Validate checks that the coordinate is set, finite and within range.
+func (c *Coordinate) Validate() error {
+	if c == nil {
+		return errors.New("map-kit: coordinate missing")
+	}
+
+	if !validLatitude(c.Lat) {
+		return fmt.Errorf("map-kit: latitude %v out of range [-90, 90]", c.Lat)
+	}
+
+	if !validLongitude(c.Lng) {
+		return fmt.Errorf("map-kit: longitude %v out of range [-180, 180]", c.Lng)
+	}
+
+	return nil
+}
+
+func validLatitude(lat float64) bool {
+	return !math.IsNaN(lat) && lat >= -90 && lat <= 90
+}
+
+func validLongitude(lng float64) bool {
+	return !math.IsNaN(lng) && lng >= -180 && lng <= 180
+}
+
+// ParseOption is the type of options for ParseCoordinateE(...).
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	lngLat bool
+}
+
+// WithLngLatOrder makes the parser read plain "lng,lat" pairs instead of the
+// default "lat,lng". Hemisphere letters and geo: URIs take precedence.
+func WithLngLatOrder() ParseOption {
+	return func(o *parseOptions) {
+		o.lngLat = true
+	}
+}
+
+var (
+	dmsComponent = `[NSEWnsew]?\s*[-+]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][-+]?\d+)?(?:\s*(?:°|º|d)\s*(?:\d+(?:\.\d+)?\s*(?:'|′)\s*(?:\d+(?:\.\d+)?\s*(?:"|″|'')\s*)?)?)?\s*[NSEWnsew]?`
+	pairPattern  = regexp.MustCompile(`^\s*(` + dmsComponent + `)\s*(?:[,;]|\s)\s*(` + dmsComponent + `)\s*$`)
+	partPattern  = regexp.MustCompile(`^([NSEWnsew]?)\s*([-+]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][-+]?\d+)?)(?:\s*(?:°|º|d)\s*(?:(\d+(?:\.\d+)?)\s*(?:'|′)\s*(?:(\d+(?:\.\d+)?)\s*(?:"|″|'')\s*)?)?)?\s*([NSEWnsew]?)$`)
+)
+
+// ParseCoordinate parses a coordinate like ParseCoordinateE, returning nil if
+// the string is not a valid coordinate.
 func ParseCoordinate(str string) *Coordinate {
-	elements := strings.Split(str, ",")
-	if len(elements) != 2 {
+	c, err := ParseCoordinateE(str)
+	if err != nil {
 		return nil
 	}
 
-	lat, err := strconv.ParseFloat(elements[0], 64)
+	return c
+}
+
+// ParseCoordinateE parses a coordinate and validates its range. Accepted forms:
+//
+//	50.038860,36.218690           decimal "lat,lng" (see WithLngLatOrder)
+//	5.003886e1,3.621869e1         exponent notation
+//	50.038860;36.218690           semicolon or whitespace separated
+//	50°02'19.9"N 36°13'07.3"E     degrees, minutes, seconds with hemispheres
+//	50.0388N, 36.2186E            decimal degrees with hemispheres
+//	geo:50.038860,36.218690;u=35  geo URI (RFC 5870)
+//
+// A hemisphere letter gives the sign, so it cannot be combined with a negative
+// value: "-33.8688S" is rejected.
+func ParseCoordinateE(str string, options ...ParseOption) (*Coordinate, error) {
+	opts := &parseOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	s := strings.TrimSpace(str)
+	if s == "" {
+		return nil, errors.New("map-kit: empty coordinate")
+	}
+
+	if len(s) > 4 && strings.EqualFold(s[:4], "geo:") {
+		return parseGeoURI(s)
+	}
+
+	m := pairPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("map-kit: unrecognized coordinate %q", str)
+	}
+
+	first, firstAxis, err := parseComponent(m[1])
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("map-kit: coordinate %q: %w", str, err)
 	}
 
-	lng, err := strconv.ParseFloat(elements[1], 64)
+	second, secondAxis, err := parseComponent(m[2])
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("map-kit: coordinate %q: %w", str, err)
+	}
+
+	switch {
+	case firstAxis != 0 && firstAxis == secondAxis:
+		return nil, fmt.Errorf("map-kit: coordinate %q: both components are on the same axis", str)
+	case firstAxis == 'x' || secondAxis == 'y':
+		first, second = second, first
+	case firstAxis == 0 && secondAxis == 0 && opts.lngLat:
+		first, second = second, first
+	}
+
+	c := &Coordinate{Lat: first, Lng: second}
+	if err := c.Validate(); err != nil {
+		return nil, err
 	}
 
-	return &Coordinate{
-		Lng: lng,
-		Lat: lat,
+	return c, nil
+}
+
+// parseComponent parses a single decimal or DMS value. The returned axis is 'y'
+// for N/S, 'x' for E/W and 0 if no hemisphere was given.
+func parseComponent(s string) (float64, byte, error) {
+	m := partPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, 0, fmt.Errorf("invalid value %q", s)
 	}
+
+	hemisphere := strings.ToUpper(m[1] + m[5])
+	if len(hemisphere) > 1 {
+		return 0, 0, fmt.Errorf("conflicting hemispheres in %q", s)
+	}
+
+	value, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	negative := value < 0 || strings.HasPrefix(m[2], "-")
+	if negative && hemisphere != "" {
+		return 0, 0, fmt.Errorf("negative value with a hemisphere in %q", s)
+	}
+	value = math.Abs(value)
+
+	if m[3] != "" {
+		minutes, _ := strconv.ParseFloat(m[3], 64)
+		if minutes >= 60 {
+			return 0, 0, fmt.Errorf("minutes out of range in %q", s)
+		}
+		value += minutes / 60
+	}
+
+	if m[4] != "" {
+		seconds, _ := strconv.ParseFloat(m[4], 64)
+		if seconds >= 60 {
+			return 0, 0, fmt.Errorf("seconds out of range in %q", s)
+		}
+		value += seconds / 3600
+	}
+
+	var axis byte
+	switch hemisphere {
+	case "N":
+		axis = 'y'
+	case "S":
+		axis, negative = 'y', !negative
+	case "E":
+		axis = 'x'
+	case "W":
+		axis, negative = 'x', !negative
+	}
+
+	if negative {
+		value = -value
+	}
+
+	return value, axis, nil
+}
+
+// parseGeoURI parses a geo URI of the form geo:lat,lng[,alt][;params].
+func parseGeoURI(s string) (*Coordinate, error) {
+	body := s[4:]
+	if i := strings.IndexByte(body, ';'); i >= 0 {
+		body = body[:i]
+	}
+
+	elements := strings.Split(body, ",")
+	if len(elements) != 2 && len(elements) != 3 {
+		return nil, fmt.Errorf("map-kit: invalid geo URI %q", s)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(elements[0]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("map-kit: invalid latitude in geo URI %q", s)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(elements[1]), 64)
+	if err != nil {
+		return nil, fmt.Errorf("map-kit: invalid longitude in geo URI %q", s)
+	}
+
+	c := &Coordinate{Lat: lat, Lng: lng}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
 }
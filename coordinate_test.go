@@ -0,0 +1,89 @@
+package go_huawei
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCoordinateE(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lng float64
+		options  []ParseOption
+	}{
+		{in: "50.038860,36.218690", lat: 50.03886, lng: 36.21869},
+		{in: " 50.038860 ; 36.218690 ", lat: 50.03886, lng: 36.21869},
+		{in: "50.038860 36.218690", lat: 50.03886, lng: 36.21869},
+		{in: "0,0"},
+		{in: "36.218690,50.038860", lat: 50.03886, lng: 36.21869, options: []ParseOption{WithLngLatOrder()}},
+		{in: `50°02'19.9"N 36°13'07.3"E`, lat: 50 + 2.0/60 + 19.9/3600, lng: 36 + 13.0/60 + 7.3/3600},
+		{in: `36°13'07.3"E 50°02'19.9"N`, lat: 50 + 2.0/60 + 19.9/3600, lng: 36 + 13.0/60 + 7.3/3600},
+		{in: "33.8688S, 151.2093E", lat: -33.8688, lng: 151.2093},
+		{in: "S33.8688 W70.6693", lat: -33.8688, lng: -70.6693},
+		{in: "geo:50.038860,36.218690;u=35", lat: 50.03886, lng: 36.21869},
+		{in: "GEO:-12.5,130.8,15", lat: -12.5, lng: 130.8},
+		{in: "1e1,2", lat: 10, lng: 2},
+		{in: "5.003886E+1, -3.621869e1", lat: 50.03886, lng: -36.21869},
+		{in: ".5,-2.", lat: 0.5, lng: -2},
+		{in: "1.5e1N 2E", lat: 15, lng: 2},
+		{in: "+0.5S 2E", lat: -0.5, lng: 2},
+	}
+
+	for _, test := range tests {
+		c, err := ParseCoordinateE(test.in, test.options...)
+		if err != nil {
+			t.Errorf("ParseCoordinateE(%q) = %v", test.in, err)
+			continue
+		}
+		if math.Abs(c.Lat-test.lat) > 1e-9 || math.Abs(c.Lng-test.lng) > 1e-9 {
+			t.Errorf("ParseCoordinateE(%q) = %v, want %v,%v", test.in, c, test.lat, test.lng)
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("parsed %q does not validate: %v", test.in, err)
+		}
+	}
+}
+
+func TestParseCoordinateEErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"50.0",
+		"abc,def",
+		"91,0",
+		"0,181",
+		"50N 36N",
+		"50°61'N 36E",
+		"N50S, 36E",
+		"-0.5S, 36E",
+		"50N, -36W",
+		"1e400,2",
+		"geo:50",
+		"geo:x,36",
+	} {
+		if c, err := ParseCoordinateE(in); err == nil {
+			t.Errorf("ParseCoordinateE(%q) = %v, want an error", in, c)
+		}
+		if c := ParseCoordinate(in); c != nil {
+			t.Errorf("ParseCoordinate(%q) = %v, want nil", in, c)
+		}
+	}
+}
+
+func TestCoordinateFormat(t *testing.T) {
+	c := &Coordinate{Lat: 50.4501, Lng: -30.5234567}
+
+	if got := c.String(); got != "50.450100,-30.523457" {
+		t.Errorf("String() = %s", got)
+	}
+	if got := c.Format(2); got != "50.45,-30.52" {
+		t.Errorf("Format(2) = %s", got)
+	}
+
+	var missing *Coordinate
+	if missing.String() != "" {
+		t.Error("nil coordinate is not formatted as empty")
+	}
+	if missing.Validate() == nil {
+		t.Error("nil coordinate validates")
+	}
+}
@@ -0,0 +1,160 @@
+// Package coordsys converts coordinates between the geodetic systems used by
+// map providers: WGS-84 (GPS), GCJ-02 (mainland China) and BD-09 (Baidu).
+//
+// GCJ-02 is only defined inside mainland China; outside it the GCJ-02 and
+// BD-09 conversions leave WGS-84 coordinates unchanged.
+package coordsys
+
+import "math"
+
+// System is a geodetic coordinate system.
+type System string
+
+// Supported coordinate systems.
+const (
+	WGS84 = System("wgs84")
+	GCJ02 = System("gcj02")
+	BD09  = System("bd09")
+)
+
+const (
+	// Krasovsky 1940 ellipsoid used by GCJ-02.
+	semiMajorAxis = 6378245.0
+	eccentricity2 = 0.00669342162296594323
+
+	bdFactor = math.Pi * 3000.0 / 180.0
+
+	maxIterations = 30
+	tolerance     = 1e-10
+)
+
+// Valid reports whether s is a supported system.
+func (s System) Valid() bool {
+	switch s {
+	case WGS84, GCJ02, BD09:
+		return true
+	}
+
+	return false
+}
+
+// Convert converts lat/lng from one system to another. Coordinates in an
+// unsupported system are returned unchanged.
+func Convert(lat, lng float64, from, to System) (float64, float64) {
+	if from == to || !from.Valid() || !to.Valid() {
+		return lat, lng
+	}
+
+	switch from {
+	case WGS84:
+		lat, lng = WGS84ToGCJ02(lat, lng)
+	case BD09:
+		lat, lng = BD09ToGCJ02(lat, lng)
+	}
+
+	switch to {
+	case WGS84:
+		lat, lng = GCJ02ToWGS84(lat, lng)
+	case BD09:
+		lat, lng = GCJ02ToBD09(lat, lng)
+	}
+
+	return lat, lng
+}
+
+// OutOfChina reports whether the point lies outside the rough bounding box of
+// mainland China, where GCJ-02 equals WGS-84.
+func OutOfChina(lat, lng float64) bool {
+	return lng < 72.004 || lng > 137.8347 || lat < 0.8293 || lat > 55.8271
+}
+
+// WGS84ToGCJ02 converts a WGS-84 point to GCJ-02.
+func WGS84ToGCJ02(lat, lng float64) (float64, float64) {
+	if OutOfChina(lat, lng) {
+		return lat, lng
+	}
+
+	dLat, dLng := delta(lat, lng)
+	return lat + dLat, lng + dLng
+}
+
+// GCJ02ToWGS84 converts a GCJ-02 point to WGS-84. The forward transform has no
+// closed-form inverse, so the result is refined iteratively to well below a
+// millimetre.
+func GCJ02ToWGS84(lat, lng float64) (float64, float64) {
+	if OutOfChina(lat, lng) {
+		return lat, lng
+	}
+
+	wgsLat, wgsLng := lat, lng
+	for i := 0; i < maxIterations; i++ {
+		gcjLat, gcjLng := WGS84ToGCJ02(wgsLat, wgsLng)
+		dLat, dLng := lat-gcjLat, lng-gcjLng
+
+		wgsLat += dLat
+		wgsLng += dLng
+
+		if math.Abs(dLat) < tolerance && math.Abs(dLng) < tolerance {
+			break
+		}
+	}
+
+	return wgsLat, wgsLng
+}
+
+// GCJ02ToBD09 converts a GCJ-02 point to BD-09.
+func GCJ02ToBD09(lat, lng float64) (float64, float64) {
+	if OutOfChina(lat, lng) {
+		return lat, lng
+	}
+
+	z := math.Sqrt(lng*lng+lat*lat) + 0.00002*math.Sin(lat*bdFactor)
+	theta := math.Atan2(lat, lng) + 0.000003*math.Cos(lng*bdFactor)
+
+	return z*math.Sin(theta) + 0.006, z*math.Cos(theta) + 0.0065
+}
+
+// BD09ToGCJ02 converts a BD-09 point to GCJ-02.
+func BD09ToGCJ02(lat, lng float64) (float64, float64) {
+	if OutOfChina(lat, lng) {
+		return lat, lng
+	}
+
+	x, y := lng-0.0065, lat-0.006
+	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*bdFactor)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bdFactor)
+
+	return z * math.Sin(theta), z * math.Cos(theta)
+}
+
+// delta returns the GCJ-02 offset of a WGS-84 point, in degrees.
+func delta(lat, lng float64) (float64, float64) {
+	dLat := transformLat(lng-105.0, lat-35.0)
+	dLng := transformLng(lng-105.0, lat-35.0)
+
+	radLat := lat / 180.0 * math.Pi
+	magic := math.Sin(radLat)
+	magic = 1 - eccentricity2*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+
+	dLat = (dLat * 180.0) / ((semiMajorAxis * (1 - eccentricity2)) / (magic * sqrtMagic) * math.Pi)
+	dLng = (dLng * 180.0) / (semiMajorAxis / sqrtMagic * math.Cos(radLat) * math.Pi)
+
+	return dLat, dLng
+}
+
+func transformLat(x, y float64) float64 {
+	ret := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	return ret
+}
+
+func transformLng(x, y float64) float64 {
+	ret := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+	return ret
+}
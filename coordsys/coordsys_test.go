@@ -0,0 +1,74 @@
+package coordsys
+
+import (
+	"math"
+	"testing"
+)
+
+// metres returns the rough distance between two nearby points.
+func metres(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * 111320
+	dLng := (lng2 - lng1) * 111320 * math.Cos(lat1*math.Pi/180)
+	return math.Hypot(dLat, dLng)
+}
+
+func TestOutsideChinaUnchanged(t *testing.T) {
+	points := [][2]float64{
+		{50.4501, 30.5234},   // Kyiv
+		{-33.8688, 151.2093}, // Sydney
+		{22.3193, 10.1694},
+	}
+
+	for _, p := range points {
+		for _, from := range []System{WGS84, GCJ02, BD09} {
+			for _, to := range []System{WGS84, GCJ02, BD09} {
+				lat, lng := Convert(p[0], p[1], from, to)
+				if lat != p[0] || lng != p[1] {
+					t.Errorf("Convert(%v, %s→%s) = %v,%v, want it unchanged", p, from, to, lat, lng)
+				}
+			}
+		}
+	}
+}
+
+func TestInsideChina(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lng float64
+	}{
+		{"Beijing", 39.9087, 116.3975},
+		{"Shanghai", 31.2304, 121.4737},
+		{"Shenzhen", 22.5431, 114.0579},
+	}
+
+	for _, test := range tests {
+		gcjLat, gcjLng := WGS84ToGCJ02(test.lat, test.lng)
+		if d := metres(test.lat, test.lng, gcjLat, gcjLng); d < 100 || d > 1000 {
+			t.Errorf("%s: GCJ-02 offset is %.0f m, want a few hundred", test.name, d)
+		}
+
+		wgsLat, wgsLng := GCJ02ToWGS84(gcjLat, gcjLng)
+		if d := metres(test.lat, test.lng, wgsLat, wgsLng); d > 1e-3 {
+			t.Errorf("%s: WGS-84 round trip is %v m off", test.name, d)
+		}
+
+		bdLat, bdLng := Convert(test.lat, test.lng, WGS84, BD09)
+		if d := metres(gcjLat, gcjLng, bdLat, bdLng); d < 500 || d > 1500 {
+			t.Errorf("%s: BD-09 offset from GCJ-02 is %.0f m", test.name, d)
+		}
+
+		backLat, backLng := Convert(bdLat, bdLng, BD09, WGS84)
+		if d := metres(test.lat, test.lng, backLat, backLng); d > 1 {
+			t.Errorf("%s: BD-09 round trip is %.2f m off", test.name, d)
+		}
+	}
+}
+
+func TestConvertUnsupported(t *testing.T) {
+	if lat, lng := Convert(39.9, 116.4, "mercator", WGS84); lat != 39.9 || lng != 116.4 {
+		t.Errorf("unsupported system converted to %v,%v", lat, lng)
+	}
+	if System("").Valid() || !BD09.Valid() {
+		t.Error("Valid is wrong")
+	}
+}
@@ -2,8 +2,6 @@ package go_huawei
 
 import (
 	"context"
-	"errors"
-	"fmt"
 )
 
 var directionsAPI = &apiConfig{
@@ -14,23 +12,18 @@ var directionsAPI = &apiConfig{
 
 // Directions issues the Directions request and retrieves the Response
 func (c *Client) Directions(ctx context.Context, directionsRequest *DirectionsRequest) ([]Route, error) {
-	if directionsRequest.Origin.isEmpty() {
-		return nil, errors.New("map-kit: origin missing")
+	if err := directionsRequest.Validate(); err != nil {
+		return nil, err
 	}
 
-	if directionsRequest.Destination.isEmpty() {
-		return nil, errors.New("map-kit: destination missing")
-	}
-	if directionsRequest.RouteService != "" &&
-		RouteServiceDriving != directionsRequest.RouteService &&
-		RouteServiceWalking != directionsRequest.RouteService &&
-		RouteServiceBicycling != directionsRequest.RouteService {
-		return nil, fmt.Errorf("map-kit: unknown RouteService: '%s'", directionsRequest.RouteService)
+	apiRequest := directionsRequest
+	if c.coordType != "" {
+		apiRequest = directionsRequest.toAPICoordinates(c.coordType)
 	}
 
 	response := DirectionsResponse{}
 
-	if err := c.postJSON(ctx, directionsAPI, directionsRequest, &response, directionsRequest.RouteService); err != nil {
+	if err := c.postJSON(ctx, directionsAPI, apiRequest, &response, directionsRequest.routeService()); err != nil {
 		return nil, err
 	}
 
@@ -39,5 +32,9 @@ func (c *Client) Directions(ctx context.Context, directionsRequest *DirectionsRe
 		return nil, e
 	}
 
+	if c.coordType != "" {
+		convertRoutes(response.Routes, c.coordType)
+	}
+
 	return response.Routes, nil
 }
@@ -37,5 +37,15 @@ type DirectionsRequest struct {
 	// Language of the distance and journey time descriptions in the returned result. Currently, only zh_CN and en are supported
 	Language string `json:"language,omitempty"`
 
+	// RouteService selects the travel mode. Default is RouteServiceDriving.
 	RouteService RouteService `json:"-"`
 }
+
+// routeService returns the RouteService the request is sent to.
+func (r *DirectionsRequest) routeService() RouteService {
+	if r.RouteService == "" {
+		return RouteServiceDriving
+	}
+
+	return r.RouteService
+}
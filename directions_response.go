@@ -1,15 +1,41 @@
 package go_huawei
 
-import "github.com/twpayne/go-polyline"
+import "encoding/json"
 
 type DirectionsResponse struct {
 	Routes []Route `json:"routes"`
 	CommonResponse
+
+	// Extra holds the members of the response that have no field here, such as
+	// ones added to the API after this package was released. They are written
+	// back by MarshalJSON.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Route struct {
 	Paths  []Path           `json:"paths"`
 	Bounds CoordinateBounds `json:"bounds"`
+
+	// HasTolls, HasFerry, HasBorder, HasRestrictedRoad and HasRoadCharges tell
+	// whether the route uses toll roads, ferries, border crossings, roads with
+	// access restrictions and roads with charges, such as congestion zones.
+	HasTolls          Flag `json:"hasTolls,omitempty"`
+	HasFerry          Flag `json:"hasFerry,omitempty"`
+	HasBorder         Flag `json:"hasBorder,omitempty"`
+	HasRestrictedRoad Flag `json:"hasRestrictedRoad,omitempty"`
+	HasRoadCharges    Flag `json:"hasRoadCharges,omitempty"`
+	// DstInRestrictedArea tells whether the destination is in a restricted
+	// area, CrossMultiCountries whether the route crosses countries and
+	// DstInDiffTimeZone whether the destination is in another time zone.
+	DstInRestrictedArea Flag `json:"dstInRestrictedArea,omitempty"`
+	CrossMultiCountries Flag `json:"crossMultiCountries,omitempty"`
+	DstInDiffTimeZone   Flag `json:"dstInDiffTimeZone,omitempty"`
+	// TrafficLightNum is the number of traffic lights along the route.
+	TrafficLightNum int `json:"trafficLightNum,omitempty"`
+
+	// Extra holds the members of the route without a field, as in
+	// DirectionsResponse.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type CoordinateBounds struct {
@@ -29,6 +55,10 @@ type Path struct {
 	Steps                 []Step     `json:"steps"`
 	EndLocation           Coordinate `json:"endLocation"`
 	EndAddress            string     `json:"endAddress"`
+
+	// Extra holds the members of the path without a field, as in
+	// DirectionsResponse.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 type Step struct {
@@ -43,24 +73,11 @@ type Step struct {
 	EndLocation   Coordinate   `json:"endLocation"`
 	Polyline      []Coordinate `json:"polyline"`
 	RoadName      string       `json:"roadName"`
-}
 
-type Action string
-
-const (
-	End             Action = "end"
-	ForkLeft        Action = "fork-left"
-	ForkRight       Action = "fork-right"
-	RampRight       Action = "ramp-right"
-	RampLeft        Action = "ramp-left"
-	RoundaboutRight Action = "roundabout-right"
-	RoundaboutLeft  Action = "roundabout-left"
-	Straight        Action = "straight"
-	TurnLeft        Action = "turn-left"
-	TurnRight       Action = "turn-right"
-	TurnSlightLeft  Action = "turn-slight-left"
-	TurnSlightRight Action = "turn-slight-right"
-)
+	// Extra holds the members of the step without a field, as in
+	// DirectionsResponse.
+	Extra map[string]json.RawMessage `json:"-"`
+}
 
 func (p *Path) Overview() []Coordinate {
 	if p == nil || p.Steps == nil {
@@ -76,17 +93,12 @@ func (p *Path) Overview() []Coordinate {
 	return overviewPath
 }
 
-func (p *Path) OverviewPolyline() []byte {
+// OverviewPolyline returns the overview geometry as an encoded polyline, at
+// precision 5 unless WithPolylinePrecision says otherwise.
+func (p *Path) OverviewPolyline(options ...PolylineOption) []byte {
 	if p == nil || p.Steps == nil {
 		return nil
 	}
 
-	overviewPath := p.Overview()
-	var coords [][]float64
-
-	for _, coordinate := range overviewPath {
-		coords = append(coords, []float64{coordinate.Lat, coordinate.Lng})
-	}
-
-	return polyline.EncodeCoords(coords)
+	return EncodePolyline(p.Overview(), options...)
 }
@@ -0,0 +1,55 @@
+package go_huawei_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+func TestDirectionsDefaultRouteService(t *testing.T) {
+	server := huaweitest.NewServer(huaweitest.WithResponder(huaweitest.NewRouter().Responder()))
+	defer server.Close()
+
+	client, err := go_huawei.NewClient(go_huawei.WithAPIKey("key"), go_huawei.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := client.Directions(context.Background(), &go_huawei.DirectionsRequest{
+		Origin:      &go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234},
+		Destination: &go_huawei.Coordinate{Lat: 50.4547, Lng: 30.5238},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 {
+		t.Fatalf("got %d routes, want 1", len(routes))
+	}
+
+	if got := server.Requests()[0].Path; got != "/mapApi/v1/routeService/driving" {
+		t.Errorf("request sent to %s, want the driving service", got)
+	}
+}
+
+func TestDirectionsValidatesBeforeSending(t *testing.T) {
+	server := huaweitest.NewServer()
+	defer server.Close()
+
+	client, err := go_huawei.NewClient(go_huawei.WithAPIKey("key"), go_huawei.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Directions(context.Background(), &go_huawei.DirectionsRequest{RouteService: go_huawei.RouteServiceWalking})
+
+	var v *go_huawei.ValidationError
+	if !errors.As(err, &v) || len(v.Errors) != 2 {
+		t.Fatalf("err = %v, want missing origin and destination", err)
+	}
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("invalid request reached the server %d times", n)
+	}
+}
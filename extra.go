@@ -0,0 +1,257 @@
+package go_huawei
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Flag is a yes/no attribute that the API sends as 0 or 1. Booleans and
+// quoted numbers are accepted as well; null leaves the flag unchanged.
+type Flag bool
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	switch string(bytes.Trim(data, `"`)) {
+	case "1", "true":
+		*f = true
+	case "0", "false", "":
+		*f = false
+	default:
+		return fmt.Errorf("map-kit: invalid flag %.20s", data)
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing 0 or 1 as the API does.
+func (f Flag) MarshalJSON() ([]byte, error) {
+	if f {
+		return []byte("1"), nil
+	}
+
+	return []byte("0"), nil
+}
+
+type (
+	directionsResponseFields DirectionsResponse
+	routeFields              Route
+	pathFields               Path
+	stepFields               Step
+)
+
+// UnmarshalJSON implements json.Unmarshaler, keeping members without a field
+// in Extra.
+func (r *DirectionsResponse) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalExtra(data, (*directionsResponseFields)(r))
+	r.Extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing Extra after the fields.
+func (r DirectionsResponse) MarshalJSON() ([]byte, error) {
+	return marshalExtra(directionsResponseFields(r), r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping members without a field
+// in Extra.
+func (r *Route) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalExtra(data, (*routeFields)(r))
+	r.Extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing Extra after the fields.
+func (r Route) MarshalJSON() ([]byte, error) {
+	return marshalExtra(routeFields(r), r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping members without a field
+// in Extra.
+func (p *Path) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalExtra(data, (*pathFields)(p))
+	p.Extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing Extra after the fields.
+func (p Path) MarshalJSON() ([]byte, error) {
+	return marshalExtra(pathFields(p), p.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping members without a field
+// in Extra.
+func (s *Step) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalExtra(data, (*stepFields)(s))
+	s.Extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing Extra after the fields.
+func (s Step) MarshalJSON() ([]byte, error) {
+	return marshalExtra(stepFields(s), s.Extra)
+}
+
+// unmarshalExtra decodes data into v, a pointer to a struct without JSON
+// methods, and returns the members of data that v has no field for, or nil if
+// there are none. Members are read once, in order, and each is decoded
+// straight into its field, so nested values are not decoded twice.
+func unmarshalExtra(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		// Let encoding/json handle null and report other values.
+		return nil, json.Unmarshal(data, v)
+	}
+
+	value := reflect.ValueOf(v).Elem()
+	fields := structFields(value.Type())
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var extra map[string]json.RawMessage
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name := token.(string)
+
+		index, ok := fields.exact[name]
+		if !ok {
+			index, ok = fields.folded[strings.ToLower(name)]
+		}
+		if ok {
+			if err := dec.Decode(value.FieldByIndex(index).Addr().Interface()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[name] = raw
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return extra, nil
+}
+
+// marshalExtra encodes v, a struct without JSON methods, followed by the extra
+// members in name order. Extra members named like a field are skipped.
+func marshalExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := structFields(reflect.TypeOf(v)).folded
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		if _, ok := known[strings.ToLower(name)]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, name := range names {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, extra[name]); err != nil {
+			return nil, fmt.Errorf("map-kit: extra member %q: %w", name, err)
+		}
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// fieldIndex maps the JSON names of the fields of a struct type to their
+// reflect indexes, both as written and lower-cased. Like encoding/json, an
+// exact name wins over a case-insensitive match.
+type fieldIndex struct {
+	exact  map[string][]int
+	folded map[string][]int
+}
+
+var fieldIndexCache sync.Map
+
+// structFields returns the fields of struct type t, including promoted ones,
+// matching how encoding/json pairs members with fields.
+func structFields(t reflect.Type) *fieldIndex {
+	if fields, ok := fieldIndexCache.Load(t); ok {
+		return fields.(*fieldIndex)
+	}
+
+	fields := &fieldIndex{exact: make(map[string][]int), folded: make(map[string][]int)}
+	fields.add(t, nil)
+	fieldIndexCache.Store(t, fields)
+
+	return fields
+}
+
+// add records the fields of t under the index prefix. Fields of t come before
+// promoted ones, so that shallower fields hide deeper ones with the same name.
+func (f *fieldIndex) add(t reflect.Type, prefix []int) {
+	var embedded []int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, i)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		index := append(append([]int(nil), prefix...), i)
+		lower := strings.ToLower(name)
+		if shallower, ok := f.folded[lower]; ok && len(shallower) < len(index) {
+			continue
+		}
+		if _, ok := f.exact[name]; !ok {
+			f.exact[name] = index
+		}
+		if _, ok := f.folded[lower]; !ok {
+			f.folded[lower] = index
+		}
+	}
+
+	for _, i := range embedded {
+		f.add(t.Field(i).Type, append(append([]int(nil), prefix...), i))
+	}
+}
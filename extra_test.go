@@ -0,0 +1,245 @@
+package go_huawei
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// extraResponse has an unknown member at every level, next to known ones.
+const extraResponse = `{
+	"returnCode": "0",
+	"returnDesc": "OK",
+	"apiVersion": "2.1",
+	"routes": [{
+		"hasTolls": 1,
+		"labels": ["fastest", "eco"],
+		"paths": [{
+			"distance": 120.5,
+			"trafficSegments": [{"from": 0, "to": 3, "speed": 42}],
+			"steps": [{
+				"action": "turn-left",
+				"roadName": "Khreshchatyk",
+				"polyline": [{"lat": 50.45, "lng": 30.52}, {"lat": 50.46, "lng": 30.53}],
+				"laneInfo": {"lanes": 3, "recommended": [1, 2]}
+			}]
+		}]
+	}]
+}`
+
+func TestExtraRoundTrip(t *testing.T) {
+	var r DirectionsResponse
+	if err := json.Unmarshal([]byte(extraResponse), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	route := r.Routes[0]
+	path := route.Paths[0]
+	step := path.Steps[0]
+	extras := []struct {
+		level string
+		extra map[string]json.RawMessage
+		name  string
+		want  string
+	}{
+		{"response", r.Extra, "apiVersion", `"2.1"`},
+		{"route", route.Extra, "labels", `["fastest", "eco"]`},
+		{"path", path.Extra, "trafficSegments", `[{"from": 0, "to": 3, "speed": 42}]`},
+		{"step", step.Extra, "laneInfo", `{"lanes": 3, "recommended": [1, 2]}`},
+	}
+	for _, e := range extras {
+		if len(e.extra) != 1 || string(e.extra[e.name]) != e.want {
+			t.Errorf("%s extra = %s, want only %s: %s", e.level, e.extra, e.name, e.want)
+		}
+	}
+
+	if r.ReturnCode != "0" || !bool(route.HasTolls) || path.Distance != 120.5 || step.Action != TurnLeft || len(step.Polyline) != 2 {
+		t.Errorf("known fields were not decoded: %+v", r)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every member comes back; known ones may gain zero values.
+	var original, encoded map[string]interface{}
+	if err := json.Unmarshal([]byte(extraResponse), &original); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		t.Fatal(err)
+	}
+	if missing := missingMembers(original, encoded, ""); len(missing) > 0 {
+		t.Errorf("members lost in the round trip: %v\n%s", missing, data)
+	}
+
+	var again DirectionsResponse
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Routes[0].Paths[0].Steps[0].Polyline, step.Polyline) {
+		t.Error("polyline changed in the round trip")
+	}
+	for i, level := range []map[string]json.RawMessage{again.Extra, again.Routes[0].Extra, again.Routes[0].Paths[0].Extra, again.Routes[0].Paths[0].Steps[0].Extra} {
+		if len(level) != 1 {
+			t.Errorf("extra at level %d after the round trip = %s", i, level)
+		}
+	}
+}
+
+// missingMembers returns the paths of the members of want that got lacks.
+func missingMembers(want, got interface{}, path string) []string {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		var missing []string
+		for name, value := range want {
+			child, ok := got[name]
+			if !ok {
+				missing = append(missing, path+"."+name)
+				continue
+			}
+			missing = append(missing, missingMembers(value, child, path+"."+name)...)
+		}
+		return missing
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(got) != len(want) {
+			return []string{path}
+		}
+		var missing []string
+		for i := range want {
+			missing = append(missing, missingMembers(want[i], got[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return missing
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		return []string{path}
+	}
+	return nil
+}
+
+func TestFlag(t *testing.T) {
+	tests := []struct {
+		value   string
+		initial Flag
+		want    Flag
+	}{
+		{`0`, true, false},
+		{`1`, false, true},
+		{`"1"`, false, true},
+		{`"0"`, true, false},
+		{`true`, false, true},
+		{`false`, true, false},
+		{`null`, true, true},
+		{`null`, false, false},
+	}
+
+	for _, test := range tests {
+		route := Route{HasFerry: test.initial}
+		if err := json.Unmarshal([]byte(`{"hasFerry":`+test.value+`}`), &route); err != nil || route.HasFerry != test.want {
+			t.Errorf("hasFerry %s over %v = %v, %v; want %v", test.value, test.initial, route.HasFerry, err, test.want)
+		}
+		if route.Extra != nil {
+			t.Errorf("hasFerry %s ended up in Extra", test.value)
+		}
+	}
+
+	for _, value := range []string{`2`, `"yes"`, `[1]`} {
+		var f Flag
+		if err := json.Unmarshal([]byte(value), &f); err == nil {
+			t.Errorf("Flag %s decoded as %v", value, f)
+		}
+	}
+
+	if data, _ := json.Marshal(Route{HasTolls: true}); !strings.Contains(string(data), `"hasTolls":1`) || strings.Contains(string(data), "hasFerry") {
+		t.Errorf("flags encoded as %s", data)
+	}
+}
+
+func TestExtraFieldMatching(t *testing.T) {
+	var step Step
+	data := `{"ROADNAME":"A4","Action":"merge","Orientation":90,"roadname2":"B1","Extra":{"x":1}}`
+	if err := json.Unmarshal([]byte(data), &step); err != nil {
+		t.Fatal(err)
+	}
+
+	if step.RoadName != "A4" || step.Action != Merge || step.Orientation != 90 {
+		t.Errorf("fields matched case-insensitively = %+v", step)
+	}
+	// Extra itself is not a member, so a member with its name is kept.
+	if len(step.Extra) != 2 || step.Extra["roadname2"] == nil || step.Extra["Extra"] == nil {
+		t.Errorf("Extra = %s", step.Extra)
+	}
+
+	// Members naming the same field are applied in order, as encoding/json
+	// does for plain structs.
+	for _, data := range []string{`{"roadName":"exact","ROADNAME":"folded"}`, `{"ROADNAME":"folded","roadName":"exact"}`} {
+		var step Step
+		var plain stepFields
+		if err := json.Unmarshal([]byte(data), &step); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(data), &plain); err != nil {
+			t.Fatal(err)
+		}
+		if step.RoadName != plain.RoadName || step.Extra != nil {
+			t.Errorf("%s: RoadName = %q, encoding/json has %q", data, step.RoadName, plain.RoadName)
+		}
+	}
+
+	// Promoted fields of CommonResponse are known too.
+	var r DirectionsResponse
+	if err := json.Unmarshal([]byte(`{"RETURNCODE":"5","returnDesc":"NOT_FOUND"}`), &r); err != nil || r.ReturnCode != "5" || r.ReturnDesc != "NOT_FOUND" || r.Extra != nil {
+		t.Errorf("common response = %+v, %v", r, err)
+	}
+}
+
+func TestExtraErrors(t *testing.T) {
+	for _, data := range []string{`[]`, `"route"`, `{"paths":{}}`, `{"hasTolls":"maybe"}`, `{"paths":[}`} {
+		var route Route
+		if err := json.Unmarshal([]byte(data), &route); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v, want an error", data, route)
+		}
+	}
+
+	route := Route{TrafficLightNum: 3}
+	if err := json.Unmarshal([]byte(`null`), &route); err != nil || route.TrafficLightNum != 3 {
+		t.Errorf("null route = %+v, %v; want it unchanged", route, err)
+	}
+}
+
+func BenchmarkUnmarshalExtra(b *testing.B) {
+	polyline := make([]Coordinate, 2000)
+	for i := range polyline {
+		polyline[i] = Coordinate{Lat: 50 + float64(i)*1e-4, Lng: 30 + float64(i)*1e-4}
+	}
+	steps := make([]Step, 20)
+	for i := range steps {
+		steps[i] = Step{Action: Straight, Polyline: polyline, Extra: map[string]json.RawMessage{"laneInfo": json.RawMessage(`{"lanes":2}`)}}
+	}
+	path := Path{Steps: steps, Extra: map[string]json.RawMessage{"trafficSegments": json.RawMessage(`[]`)}}
+	response := DirectionsResponse{Routes: []Route{{Paths: []Path{path, path, path}}}}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var r DirectionsResponse
+		if err := json.Unmarshal(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package go_huawei
+
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadius is the mean radius of the Earth, in metres, used by the spherical
+// helpers.
+const EarthRadius = 6371008.8
+
+// WGS-84 ellipsoid used by VincentyDistance.
+const (
+	wgs84SemiMajorAxis = 6378137.0
+	wgs84Flattening    = 1 / 298.257223563
+	wgs84SemiMinorAxis = wgs84SemiMajorAxis * (1 - wgs84Flattening)
+
+	vincentyMaxIterations = 200
+	vincentyTolerance     = 1e-12
+)
+
+// ErrVincentyNoConvergence is returned by VincentyDistance for nearly antipodal
+// points, where the inverse formula fails to converge.
+var ErrVincentyNoConvergence = errors.New("map-kit: vincenty formula failed to converge")
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// normalizeLng wraps a longitude into [-180, 180). Longitudes already in range
+// are returned unchanged, without rounding.
+func normalizeLng(lng float64) float64 {
+	if lng >= -180 && lng < 180 {
+		return lng
+	}
+
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
+
+// DistanceTo returns the great-circle (haversine) distance to other, in metres.
+func (c *Coordinate) DistanceTo(other Coordinate) float64 {
+	lat1, lat2 := toRadians(c.Lat), toRadians(other.Lat)
+	dLat := lat2 - lat1
+	dLng := toRadians(other.Lng - c.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BearingTo returns the initial bearing of the great circle to other, in degrees
+// clockwise from north in [0, 360).
+func (c *Coordinate) BearingTo(other Coordinate) float64 {
+	lat1, lat2 := toRadians(c.Lat), toRadians(other.Lat)
+	dLng := toRadians(other.Lng - c.Lng)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(toDegrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached after travelling distance metres along
+// the great circle starting at the given bearing.
+func (c *Coordinate) Destination(bearing, distance float64) Coordinate {
+	lat1, lng1 := toRadians(c.Lat), toRadians(c.Lng)
+	theta := toRadians(bearing)
+	delta := distance / EarthRadius
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	return Coordinate{Lat: toDegrees(lat2), Lng: normalizeLng(toDegrees(lng2)), CoordType: c.CoordType}
+}
+
+// Midpoint returns the point halfway along the great circle to other.
+func (c *Coordinate) Midpoint(other Coordinate) Coordinate {
+	return c.Interpolate(other, 0.5)
+}
+
+// Interpolate returns the point at the given fraction of the great circle to
+// other: 0 is c, 1 is other.
+func (c *Coordinate) Interpolate(other Coordinate, fraction float64) Coordinate {
+	if fraction <= 0 {
+		return *c
+	}
+	if fraction >= 1 {
+		return other
+	}
+
+	d := c.DistanceTo(other) / EarthRadius
+	if d == 0 {
+		return *c
+	}
+
+	lat1, lng1 := toRadians(c.Lat), toRadians(c.Lng)
+	lat2, lng2 := toRadians(other.Lat), toRadians(other.Lng)
+
+	a := math.Sin((1-fraction)*d) / math.Sin(d)
+	b := math.Sin(fraction*d) / math.Sin(d)
+
+	x := a*math.Cos(lat1)*math.Cos(lng1) + b*math.Cos(lat2)*math.Cos(lng2)
+	y := a*math.Cos(lat1)*math.Sin(lng1) + b*math.Cos(lat2)*math.Sin(lng2)
+	z := a*math.Sin(lat1) + b*math.Sin(lat2)
+
+	return Coordinate{
+		Lat:       toDegrees(math.Atan2(z, math.Sqrt(x*x+y*y))),
+		Lng:       toDegrees(math.Atan2(y, x)),
+		CoordType: c.CoordType,
+	}
+}
+
+// VincentyDistance returns the distance between a and b on the WGS-84 ellipsoid,
+// in metres, accurate to within a millimetre. It returns
+// ErrVincentyNoConvergence for nearly antipodal points.
+func VincentyDistance(a, b Coordinate) (float64, error) {
+	const f = wgs84Flattening
+
+	L := toRadians(b.Lng - a.Lng)
+	U1 := math.Atan((1 - f) * math.Tan(toRadians(a.Lat)))
+	U2 := math.Atan((1 - f) * math.Tan(toRadians(b.Lat)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cos2Alpha, cos2SigmaM float64
+
+	converged := false
+	for i := 0; i < vincentyMaxIterations; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+
+		sinSigma = math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) +
+			(cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+		if sinSigma == 0 {
+			return 0, nil
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha = 1 - sinAlpha*sinAlpha
+
+		cos2SigmaM = 0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+
+		C := f / 16 * cos2Alpha * (4 + f*(4-3*cos2Alpha))
+		previous := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-previous) < vincentyTolerance {
+			converged = true
+			break
+		}
+	}
+
+	if !converged {
+		return 0, ErrVincentyNoConvergence
+	}
+
+	a2, b2 := wgs84SemiMajorAxis*wgs84SemiMajorAxis, wgs84SemiMinorAxis*wgs84SemiMinorAxis
+	u2 := cos2Alpha * (a2 - b2) / b2
+	A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	return wgs84SemiMinorAxis * A * (sigma - deltaSigma), nil
+}
+
+// PathLength returns the great-circle length of a polyline, in metres.
+func PathLength(coords []Coordinate) float64 {
+	var length float64
+	for i := 1; i < len(coords); i++ {
+		length += coords[i-1].DistanceTo(coords[i])
+	}
+
+	return length
+}
@@ -0,0 +1,155 @@
+package go_huawei
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// dms converts degrees, minutes and seconds to decimal degrees.
+func dms(degrees, minutes, seconds float64) float64 {
+	if degrees < 0 {
+		return degrees - minutes/60 - seconds/3600
+	}
+
+	return degrees + minutes/60 + seconds/3600
+}
+
+// Reference points of the worked examples at movable-type.co.uk (spherical,
+// R = 6371 km) and of Vincenty's own test (Geoscience Australia).
+var (
+	landsEnd      = Coordinate{Lat: dms(50, 3, 59), Lng: dms(-5, 42, 53)}
+	johnOGroats   = Coordinate{Lat: dms(58, 38, 38), Lng: dms(-3, 4, 12)}
+	flindersPeak  = Coordinate{Lat: dms(-37, 57, 3.72030), Lng: dms(144, 25, 29.52440)}
+	buninyong     = Coordinate{Lat: dms(-37, 39, 10.15610), Lng: dms(143, 55, 35.38390)}
+	equatorDegree = EarthRadius * math.Pi / 180
+)
+
+func TestDistanceTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Coordinate
+		want, tol float64
+	}{
+		{"Land's End to John o' Groats", landsEnd, johnOGroats, 968.9e3 * EarthRadius / 6371e3, 50},
+		{"one degree of the equator", Coordinate{}, Coordinate{Lng: 1}, equatorDegree, 1e-6},
+		{"across the antimeridian", Coordinate{Lng: 179.5}, Coordinate{Lng: -179.5}, equatorDegree, 1e-6},
+		{"pole to equator", Coordinate{Lat: 90}, Coordinate{Lng: 42}, 90 * equatorDegree, 1e-6},
+		{"same point", flindersPeak, flindersPeak, 0, 0},
+	}
+
+	for _, test := range tests {
+		if got := test.a.DistanceTo(test.b); math.Abs(got-test.want) > test.tol {
+			t.Errorf("%s: DistanceTo = %.3f m, want %.3f m", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBearingTo(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Coordinate
+		want float64
+	}{
+		{"Land's End to John o' Groats", landsEnd, johnOGroats, dms(9, 7, 11)},
+		{"due east", Coordinate{}, Coordinate{Lng: 1}, 90},
+		{"due west across the antimeridian", Coordinate{Lng: -179.5}, Coordinate{Lng: 179.5}, 270},
+		{"due south", Coordinate{Lat: 10}, Coordinate{Lat: -10}, 180},
+		{"due north", Coordinate{Lat: -10, Lng: 5}, Coordinate{Lat: 10, Lng: 5}, 0},
+	}
+
+	for _, test := range tests {
+		if got := test.a.BearingTo(test.b); math.Abs(got-test.want) > 1.0/3600 {
+			t.Errorf("%s: BearingTo = %.5f°, want %.5f°", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name              string
+		start             Coordinate
+		bearing, distance float64
+		want              Coordinate
+	}{
+		{
+			name:     "movable-type example",
+			start:    Coordinate{Lat: dms(53, 19, 14), Lng: dms(-1, 43, 47)},
+			bearing:  dms(96, 1, 18),
+			distance: 124.8e3,
+			want:     Coordinate{Lat: dms(53, 11, 18), Lng: dms(0, 8, 0)},
+		},
+		{
+			name:     "east across the antimeridian",
+			start:    Coordinate{Lng: 179.5},
+			bearing:  90,
+			distance: equatorDegree,
+			want:     Coordinate{Lng: -179.5},
+		},
+		{
+			name:     "over the pole",
+			start:    Coordinate{Lat: 89, Lng: 10},
+			bearing:  0,
+			distance: 2 * equatorDegree,
+			want:     Coordinate{Lat: 89, Lng: -170},
+		},
+	}
+
+	for _, test := range tests {
+		got := test.start.Destination(test.bearing, test.distance)
+		if got.DistanceTo(test.want) > 30 {
+			t.Errorf("%s: Destination = %v, want %v", test.name, &got, &test.want)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	got := landsEnd.Midpoint(johnOGroats)
+	want := Coordinate{Lat: dms(54, 21, 44), Lng: dms(-4, 31, 50)}
+	if d := got.DistanceTo(want); d > 30 {
+		t.Errorf("Midpoint = %v, want %v (%.0f m off)", &got, &want, d)
+	}
+
+	west := Coordinate{Lng: 179}
+	if mid := west.Midpoint(Coordinate{Lng: -179}); math.Abs(math.Abs(mid.Lng)-180) > 1e-9 || math.Abs(mid.Lat) > 1e-9 {
+		t.Errorf("Midpoint across the antimeridian = %v, want 0,180", &mid)
+	}
+}
+
+func TestVincentyDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Coordinate
+		want float64
+		err  error
+	}{
+		{name: "Flinders Peak to Buninyong", a: flindersPeak, b: buninyong, want: 54972.271},
+		{name: "reverse", a: buninyong, b: flindersPeak, want: 54972.271},
+		{name: "quarter meridian", a: Coordinate{}, b: Coordinate{Lat: 90}, want: 10001965.729},
+		{name: "same point", a: buninyong, b: buninyong, want: 0},
+		{name: "nearly antipodal", a: Coordinate{}, b: Coordinate{Lat: 0.5, Lng: 179.7}, err: ErrVincentyNoConvergence},
+		{name: "antipodal", a: Coordinate{Lat: 10, Lng: 20}, b: Coordinate{Lat: -10, Lng: -160}, err: ErrVincentyNoConvergence},
+	}
+
+	for _, test := range tests {
+		got, err := VincentyDistance(test.a, test.b)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: err = %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if math.Abs(got-test.want) > 1e-3 {
+			t.Errorf("%s: VincentyDistance = %.4f m, want %.3f m", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPathLength(t *testing.T) {
+	coords := []Coordinate{{}, {Lng: 1}, {Lng: 1, Lat: 1}, {Lng: 1, Lat: 1}}
+	want := 2 * equatorDegree
+	if got := PathLength(coords); math.Abs(got-want) > 1e-6 {
+		t.Errorf("PathLength = %v, want %v", got, want)
+	}
+	if PathLength(coords[:1]) != 0 || PathLength(nil) != 0 {
+		t.Error("PathLength of fewer than two points is not 0")
+	}
+}
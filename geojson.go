@@ -0,0 +1,359 @@
+package go_huawei
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Feature kinds written to the "kind" property of GeoJSON features.
+const (
+	geoJSONKindPath = "path"
+	geoJSONKindStep = "step"
+)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	BBox     []float64        `json:"bbox,omitempty"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   *geoJSONGeometry  `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Kind      string `json:"kind,omitempty"`
+	PathIndex int    `json:"pathIndex"`
+	StepIndex *int   `json:"stepIndex,omitempty"`
+
+	Distance              float64 `json:"distance"`
+	DistanceText          string  `json:"distanceText,omitempty"`
+	Duration              float64 `json:"duration"`
+	DurationText          string  `json:"durationText,omitempty"`
+	DurationInTraffic     float64 `json:"durationInTraffic,omitempty"`
+	DurationInTrafficText string  `json:"durationInTrafficText,omitempty"`
+	StartAddress          string  `json:"startAddress,omitempty"`
+	EndAddress            string  `json:"endAddress,omitempty"`
+
+	Instruction string `json:"instruction,omitempty"`
+	Action      Action `json:"action,omitempty"`
+	RoadName    string `json:"roadName,omitempty"`
+	Orientation int64  `json:"orientation,omitempty"`
+}
+
+// MarshalGeoJSON encodes the route as an RFC 7946 FeatureCollection: for every
+// path a LineString of its overview geometry followed by a LineString per step.
+// A geometry of a single position, such as a step that does not move, is
+// written as a Point. Features carry a "kind" property of "path" or "step" and
+// the path and step attributes; positions are in [lng, lat] order.
+func (r *Route) MarshalGeoJSON() ([]byte, error) {
+	collection := newGeoJSONFeatureCollection()
+	if !r.Bounds.IsEmpty() {
+		collection.BBox = []float64{r.Bounds.Southwest.Lng, r.Bounds.Southwest.Lat, r.Bounds.Northeast.Lng, r.Bounds.Northeast.Lat}
+	}
+
+	for i := range r.Paths {
+		collection.Features = append(collection.Features, r.Paths[i].geoJSONFeatures(i)...)
+	}
+
+	return json.Marshal(collection)
+}
+
+// MarshalGeoJSON encodes the path as an RFC 7946 FeatureCollection with the
+// overview LineString followed by a LineString per step. See
+// Route.MarshalGeoJSON.
+func (p *Path) MarshalGeoJSON() ([]byte, error) {
+	collection := newGeoJSONFeatureCollection()
+	collection.Features = p.geoJSONFeatures(0)
+
+	return json.Marshal(collection)
+}
+
+// MarshalGeoJSON encodes the step as an RFC 7946 FeatureCollection holding the
+// step LineString. See Route.MarshalGeoJSON.
+func (s *Step) MarshalGeoJSON() ([]byte, error) {
+	collection := newGeoJSONFeatureCollection()
+	collection.Features = []geoJSONFeature{s.geoJSONFeature(0, 0)}
+
+	return json.Marshal(collection)
+}
+
+// UnmarshalGeoJSON decodes a FeatureCollection written by MarshalGeoJSON.
+// LineString features without a "kind" property are read as paths with a
+// single step, so plain GeoJSON lines can be imported too; other features
+// without one are skipped. A path feature without step features gets its
+// geometry as the single step.
+func (r *Route) UnmarshalGeoJSON(data []byte) error {
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return NewGoHuaweiError("unmarshal geojson", err)
+	}
+
+	if collection.Type != "FeatureCollection" {
+		return NewGoHuaweiError("unmarshal geojson", fmt.Errorf("expected FeatureCollection, got %q", collection.Type))
+	}
+
+	route := Route{}
+	paths := make(map[int]*Path)
+	// lines holds the geometry of path features, the only step of a path
+	// without step features.
+	lines := make(map[int]Step)
+	var order []int
+	// Features without a kind get negative indices so that they never merge
+	// with paths written by MarshalGeoJSON.
+	anonymous := 0
+	pathAt := func(i int) *Path {
+		if p, ok := paths[i]; ok {
+			return p
+		}
+		paths[i] = &Path{}
+		order = append(order, i)
+		return paths[i]
+	}
+
+	for _, feature := range collection.Features {
+		coords, err := feature.Geometry.coordinates()
+		if err != nil {
+			return NewGoHuaweiError("unmarshal geojson", err)
+		}
+
+		props := feature.Properties
+		switch props.Kind {
+		case geoJSONKindStep:
+			path := pathAt(props.PathIndex)
+			path.Steps = append(path.Steps, props.step(coords))
+		case geoJSONKindPath:
+			props.applyToPath(pathAt(props.PathIndex), coords)
+			if coords != nil {
+				lines[props.PathIndex] = props.step(coords)
+			}
+		case "":
+			if feature.Geometry == nil || feature.Geometry.Type != "LineString" {
+				continue
+			}
+			anonymous--
+			path := pathAt(anonymous)
+			props.applyToPath(path, coords)
+			path.Steps = append(path.Steps, props.step(coords))
+		}
+	}
+
+	var overview []Coordinate
+	for _, i := range order {
+		if line, ok := lines[i]; ok && len(paths[i].Steps) == 0 {
+			paths[i].Steps = []Step{line}
+		}
+		route.Paths = append(route.Paths, *paths[i])
+		overview = append(overview, paths[i].Overview()...)
+	}
+
+	if len(collection.BBox) == 4 {
+		route.Bounds = CoordinateBounds{
+			Southwest: Coordinate{Lng: collection.BBox[0], Lat: collection.BBox[1]},
+			Northeast: Coordinate{Lng: collection.BBox[2], Lat: collection.BBox[3]},
+		}
+	} else {
+		route.Bounds = NewCoordinateBounds(overview)
+	}
+
+	*r = route
+	return nil
+}
+
+// UnmarshalGeoJSON decodes a FeatureCollection written by MarshalGeoJSON into
+// the path. If the collection holds several paths the first one is used.
+func (p *Path) UnmarshalGeoJSON(data []byte) error {
+	var route Route
+	if err := route.UnmarshalGeoJSON(data); err != nil {
+		return err
+	}
+
+	if len(route.Paths) == 0 {
+		return NewGoHuaweiError("unmarshal geojson", errors.New("no path features"))
+	}
+
+	*p = route.Paths[0]
+	return nil
+}
+
+func newGeoJSONFeatureCollection() *geoJSONFeatureCollection {
+	return &geoJSONFeatureCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+}
+
+func (p *Path) geoJSONFeatures(pathIndex int) []geoJSONFeature {
+	features := []geoJSONFeature{{
+		Type:     "Feature",
+		Geometry: newGeoJSONGeometry(RemoveDuplicates(p.Overview())),
+		Properties: geoJSONProperties{
+			Kind:                  geoJSONKindPath,
+			PathIndex:             pathIndex,
+			Distance:              p.Distance,
+			DistanceText:          p.DistanceText,
+			Duration:              p.Duration,
+			DurationText:          p.DurationText,
+			DurationInTraffic:     p.DurationInTraffic,
+			DurationInTrafficText: p.DurationInTrafficText,
+			StartAddress:          p.StartAddress,
+			EndAddress:            p.EndAddress,
+		},
+	}}
+
+	for i := range p.Steps {
+		features = append(features, p.Steps[i].geoJSONFeature(pathIndex, i))
+	}
+
+	return features
+}
+
+func (s *Step) geoJSONFeature(pathIndex, stepIndex int) geoJSONFeature {
+	polyline := s.Polyline
+	if len(polyline) == 0 {
+		polyline = []Coordinate{s.StartLocation, s.EndLocation}
+	}
+
+	return geoJSONFeature{
+		Type:     "Feature",
+		Geometry: newGeoJSONGeometry(polyline),
+		Properties: geoJSONProperties{
+			Kind:         geoJSONKindStep,
+			PathIndex:    pathIndex,
+			StepIndex:    &stepIndex,
+			Distance:     s.Distance,
+			DistanceText: s.DistanceText,
+			Duration:     s.Duration,
+			DurationText: s.DurationText,
+			Instruction:  s.Instruction,
+			Action:       s.Action,
+			RoadName:     s.RoadName,
+			Orientation:  s.Orientation,
+		},
+	}
+}
+
+// newGeoJSONGeometry returns a LineString geometry, a Point for a single
+// position or nil (a null geometry) for none.
+func newGeoJSONGeometry(coords []Coordinate) *geoJSONGeometry {
+	if len(coords) == 0 {
+		return nil
+	}
+
+	positions := make([][]float64, len(coords))
+	for i, c := range coords {
+		positions[i] = []float64{c.Lng, c.Lat}
+	}
+
+	geometryType := "LineString"
+	if len(coords) == 1 {
+		geometryType = "Point"
+	}
+
+	return &geoJSONGeometry{Type: geometryType, Coordinates: positions}
+}
+
+// MarshalJSON writes the single position of a Point unnested.
+func (g *geoJSONGeometry) MarshalJSON() ([]byte, error) {
+	if g.Type != "Point" || len(g.Coordinates) != 1 {
+		type geometry geoJSONGeometry
+		return json.Marshal((*geometry)(g))
+	}
+
+	return json.Marshal(struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	}{g.Type, g.Coordinates[0]})
+}
+
+// UnmarshalJSON decodes the coordinates of LineString and Point geometries
+// only, so that collections mixing in polygons can still be read.
+func (g *geoJSONGeometry) UnmarshalJSON(data []byte) error {
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &geometry); err != nil {
+		return err
+	}
+
+	g.Type = geometry.Type
+	if len(geometry.Coordinates) == 0 {
+		return nil
+	}
+
+	switch g.Type {
+	case "LineString":
+		return json.Unmarshal(geometry.Coordinates, &g.Coordinates)
+	case "Point":
+		var position []float64
+		if err := json.Unmarshal(geometry.Coordinates, &position); err != nil {
+			return err
+		}
+		g.Coordinates = [][]float64{position}
+	}
+
+	return nil
+}
+
+func (g *geoJSONGeometry) coordinates() ([]Coordinate, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	if g.Type != "LineString" && g.Type != "Point" {
+		return nil, nil
+	}
+
+	coords := make([]Coordinate, len(g.Coordinates))
+	for i, position := range g.Coordinates {
+		if len(position) < 2 {
+			return nil, fmt.Errorf("position %d has %d elements", i, len(position))
+		}
+		coords[i] = Coordinate{Lng: position[0], Lat: position[1]}
+	}
+
+	return coords, nil
+}
+
+func (p geoJSONProperties) applyToPath(path *Path, coords []Coordinate) {
+	path.Distance = p.Distance
+	path.DistanceText = p.DistanceText
+	path.Duration = p.Duration
+	path.DurationText = p.DurationText
+	path.DurationInTraffic = p.DurationInTraffic
+	path.DurationInTrafficText = p.DurationInTrafficText
+	path.StartAddress = p.StartAddress
+	path.EndAddress = p.EndAddress
+
+	if len(coords) > 0 {
+		path.StartLocation = coords[0]
+		path.EndLocation = coords[len(coords)-1]
+	}
+}
+
+func (p geoJSONProperties) step(coords []Coordinate) Step {
+	step := Step{
+		Distance:     p.Distance,
+		DistanceText: p.DistanceText,
+		Duration:     p.Duration,
+		DurationText: p.DurationText,
+		Instruction:  p.Instruction,
+		Action:       p.Action,
+		RoadName:     p.RoadName,
+		Orientation:  p.Orientation,
+		Polyline:     coords,
+	}
+
+	if len(coords) > 0 {
+		step.StartLocation = coords[0]
+		step.EndLocation = coords[len(coords)-1]
+	}
+
+	return step
+}
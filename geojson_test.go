@@ -0,0 +1,211 @@
+package go_huawei_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+func TestGeoJSONRoundTrip(t *testing.T) {
+	routes := huaweitest.NewRouter().Route(&go_huawei.DirectionsRequest{
+		Origin:       &go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234},
+		Destination:  &go_huawei.Coordinate{Lat: 50.4021, Lng: 30.6321},
+		Alternatives: true,
+	})
+	route := routes[0]
+	route.Paths = append(route.Paths, routes[1].Paths...)
+
+	data, err := route.MarshalGeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded go_huawei.Route
+	if err := decoded.UnmarshalGeoJSON(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Bounds.Southwest != route.Bounds.Southwest || decoded.Bounds.Northeast != route.Bounds.Northeast {
+		t.Errorf("bounds = %+v, want %+v", decoded.Bounds, route.Bounds)
+	}
+	if len(decoded.Paths) != len(route.Paths) {
+		t.Fatalf("decoded %d paths, want %d", len(decoded.Paths), len(route.Paths))
+	}
+
+	for i, want := range route.Paths {
+		got := decoded.Paths[i]
+		if got.Distance != want.Distance || got.Duration != want.Duration || got.DurationText != want.DurationText ||
+			got.StartLocation != want.StartLocation || got.EndLocation != want.EndLocation {
+			t.Errorf("path %d = %+v, want %+v", i, got, want)
+		}
+		if len(got.Steps) != len(want.Steps) {
+			t.Fatalf("path %d has %d steps, want %d", i, len(got.Steps), len(want.Steps))
+		}
+
+		for j := range want.Steps {
+			// Extra members are not part of the GeoJSON properties.
+			step := want.Steps[j]
+			step.Extra = nil
+			if !reflect.DeepEqual(got.Steps[j], step) {
+				t.Errorf("path %d step %d = %+v\nwant %+v", i, j, got.Steps[j], step)
+			}
+		}
+	}
+}
+
+func TestGeoJSONFeatures(t *testing.T) {
+	path := go_huawei.Path{
+		Distance: 120,
+		Steps: []go_huawei.Step{
+			{Polyline: []go_huawei.Coordinate{{Lng: 1, Lat: 2}, {Lng: 3, Lat: 4}}, Action: go_huawei.TurnLeft},
+			{StartLocation: go_huawei.Coordinate{Lng: 3, Lat: 4}, EndLocation: go_huawei.Coordinate{Lng: 5, Lat: 6}},
+		},
+	}
+
+	data, err := path.MarshalGeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var collection struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates [][]float64
+			}
+			Properties map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 3 {
+		t.Fatalf("collection = %s", data)
+	}
+
+	overview := collection.Features[0]
+	if overview.Properties["kind"] != "path" || !reflect.DeepEqual(overview.Geometry.Coordinates, [][]float64{{1, 2}, {3, 4}}) {
+		t.Errorf("overview feature = %+v", overview)
+	}
+	if step := collection.Features[1]; step.Properties["kind"] != "step" || step.Properties["action"] != "turn-left" || step.Properties["stepIndex"] != 0.0 {
+		t.Errorf("first step feature = %+v", step)
+	}
+	if step := collection.Features[2]; !reflect.DeepEqual(step.Geometry.Coordinates, [][]float64{{3, 4}, {5, 6}}) {
+		t.Errorf("step without polyline = %+v, want its start and end", step.Geometry)
+	}
+}
+
+func TestUnmarshalPlainGeoJSON(t *testing.T) {
+	data := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[30, 50], [30.1, 50.1, 120]]}, "properties": {"distance": 13000}},
+			{"type": "Feature", "geometry": {"type": "Point", "coordinates": [30, 50]}, "properties": {}},
+			{"type": "Feature", "geometry": null, "properties": {}},
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[31, 51], [31.1, 51.1]]}, "properties": null}
+		]
+	}`)
+
+	var route go_huawei.Route
+	if err := route.UnmarshalGeoJSON(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(route.Paths) != 2 {
+		t.Fatalf("decoded %d paths, want one per LineString", len(route.Paths))
+	}
+	first := route.Paths[0]
+	if first.Distance != 13000 || len(first.Steps) != 1 || first.EndLocation != (go_huawei.Coordinate{Lng: 30.1, Lat: 50.1}) {
+		t.Errorf("first path = %+v", first)
+	}
+	if route.Bounds.Southwest != (go_huawei.Coordinate{Lng: 30, Lat: 50}) || route.Bounds.Northeast != (go_huawei.Coordinate{Lng: 31.1, Lat: 51.1}) {
+		t.Errorf("bounds = %+v, want them computed from the lines", route.Bounds)
+	}
+
+	var path go_huawei.Path
+	if err := path.UnmarshalGeoJSON(data); err != nil || path.Distance != 13000 {
+		t.Errorf("Path.UnmarshalGeoJSON = %+v, %v; want the first path", path, err)
+	}
+}
+
+func TestGeoJSONSinglePointStep(t *testing.T) {
+	stop := go_huawei.Coordinate{Lng: 3, Lat: 4}
+	path := go_huawei.Path{Steps: []go_huawei.Step{
+		{Polyline: []go_huawei.Coordinate{{Lng: 1, Lat: 2}, stop}},
+		{Polyline: []go_huawei.Coordinate{stop}, Duration: 30},
+	}}
+
+	data, err := path.MarshalGeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var collection struct {
+		Features []struct {
+			Geometry json.RawMessage
+		}
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(collection.Features[2].Geometry); got != `{"type":"Point","coordinates":[3,4]}` {
+		t.Errorf("single-point step geometry = %s", got)
+	}
+
+	var decoded go_huawei.Path
+	if err := decoded.UnmarshalGeoJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Steps) != 2 || !reflect.DeepEqual(decoded.Steps[1].Polyline, []go_huawei.Coordinate{stop}) || decoded.Steps[1].Duration != 30 {
+		t.Errorf("decoded steps = %+v", decoded.Steps)
+	}
+}
+
+func TestUnmarshalPathFeatureWithoutSteps(t *testing.T) {
+	data := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[30, 50], [30.1, 50.1]]}, "properties": {"kind": "path", "pathIndex": 0, "distance": 13000}},
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[31, 51], [31.1, 51.1]]}, "properties": {"kind": "path", "pathIndex": 1}},
+			{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[31, 51], [31.2, 51.2]]}, "properties": {"kind": "step", "pathIndex": 1, "stepIndex": 0}}
+		]
+	}`)
+
+	var route go_huawei.Route
+	if err := route.UnmarshalGeoJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Paths) != 2 {
+		t.Fatalf("decoded %d paths, want 2", len(route.Paths))
+	}
+
+	want := []go_huawei.Coordinate{{Lng: 30, Lat: 50}, {Lng: 30.1, Lat: 50.1}}
+	if got := route.Paths[0].Overview(); !reflect.DeepEqual(got, want) {
+		t.Errorf("path without steps = %v, want its own line %v", got, want)
+	}
+	if got := route.Paths[1].Overview(); len(got) != 2 || got[1] != (go_huawei.Coordinate{Lng: 31.2, Lat: 51.2}) {
+		t.Errorf("path with steps = %v, want the step line", got)
+	}
+	if route.Bounds.Southwest != want[0] {
+		t.Errorf("bounds = %+v, want them to include the path line", route.Bounds)
+	}
+}
+
+func TestUnmarshalGeoJSONErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"not JSON":           `{`,
+		"single feature":     `{"type": "Feature"}`,
+		"short position":     `{"type": "FeatureCollection", "features": [{"geometry": {"type": "LineString", "coordinates": [[30]]}}]}`,
+		"no path for a Path": `{"type": "FeatureCollection", "features": []}`,
+	} {
+		var path go_huawei.Path
+		if err := path.UnmarshalGeoJSON([]byte(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
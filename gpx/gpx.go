@@ -0,0 +1,232 @@
+// Package gpx writes routes as GPX 1.1 documents for GPS navigators and reads
+// recorded GPS tracks back for comparison against planned paths.
+package gpx
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+const (
+	namespace      = "http://www.topografix.com/GPX/1/1"
+	schemaLocation = "http://www.topografix.com/GPX/1/1 http://www.topografix.com/GPX/1/1/gpx.xsd"
+	defaultCreator = "go-huawei"
+)
+
+type document struct {
+	XMLName        xml.Name  `xml:"gpx"`
+	Version        string    `xml:"version,attr"`
+	Creator        string    `xml:"creator,attr"`
+	Xmlns          string    `xml:"xmlns,attr,omitempty"`
+	XmlnsXSI       string    `xml:"xmlns:xsi,attr,omitempty"`
+	SchemaLocation string    `xml:"xsi:schemaLocation,attr,omitempty"`
+	Metadata       *metadata `xml:"metadata,omitempty"`
+	Routes         []route   `xml:"rte"`
+	Tracks         []track   `xml:"trk"`
+}
+
+type metadata struct {
+	Name string `xml:"name,omitempty"`
+	Time string `xml:"time,omitempty"`
+}
+
+type route struct {
+	Name   string  `xml:"name,omitempty"`
+	Desc   string  `xml:"desc,omitempty"`
+	Points []point `xml:"rtept"`
+}
+
+type track struct {
+	Name     string         `xml:"name,omitempty"`
+	Segments []trackSegment `xml:"trkseg"`
+}
+
+type trackSegment struct {
+	Points []point `xml:"trkpt"`
+}
+
+type point struct {
+	Lat       string `xml:"lat,attr"`
+	Lon       string `xml:"lon,attr"`
+	Elevation string `xml:"ele,omitempty"`
+	Time      string `xml:"time,omitempty"`
+	Name      string `xml:"name,omitempty"`
+	Desc      string `xml:"desc,omitempty"`
+	Type      string `xml:"type,omitempty"`
+}
+
+// Option is the type of options for WriteRoutes(...) and WritePath(...).
+type Option func(*writer)
+
+// WithName sets the name of the document and the prefix of route and track
+// names.
+func WithName(name string) Option {
+	return func(w *writer) {
+		w.name = name
+	}
+}
+
+// WithCreator sets the creator attribute of the document. Default is
+// "go-huawei".
+func WithCreator(creator string) Option {
+	return func(w *writer) {
+		w.creator = creator
+	}
+}
+
+// WithTime sets the time recorded in the document metadata.
+func WithTime(t time.Time) Option {
+	return func(w *writer) {
+		w.time = t
+	}
+}
+
+// WithoutTracks omits the trk elements, leaving only the maneuver routes.
+func WithoutTracks() Option {
+	return func(w *writer) {
+		w.noTracks = true
+	}
+}
+
+type writer struct {
+	name     string
+	creator  string
+	time     time.Time
+	noTracks bool
+}
+
+// WriteRoutes writes the routes as a GPX 1.1 document. Every path becomes an
+// rte with an rtept per maneuver, named after the step instruction, and a trk
+// following the full overview geometry.
+func WriteRoutes(w io.Writer, routes []go_huawei.Route, options ...Option) error {
+	var paths []*go_huawei.Path
+	for i := range routes {
+		for j := range routes[i].Paths {
+			paths = append(paths, &routes[i].Paths[j])
+		}
+	}
+
+	return write(w, paths, options)
+}
+
+// WritePath writes a single path as a GPX 1.1 document. See WriteRoutes.
+func WritePath(w io.Writer, path *go_huawei.Path, options ...Option) error {
+	return write(w, []*go_huawei.Path{path}, options)
+}
+
+func write(w io.Writer, paths []*go_huawei.Path, options []Option) error {
+	cfg := &writer{creator: defaultCreator}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	doc := document{
+		Version:        "1.1",
+		Creator:        cfg.creator,
+		Xmlns:          namespace,
+		XmlnsXSI:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: schemaLocation,
+	}
+	if cfg.name != "" || !cfg.time.IsZero() {
+		doc.Metadata = &metadata{Name: cfg.name, Time: formatTime(cfg.time)}
+	}
+
+	for i, path := range paths {
+		if path == nil {
+			continue
+		}
+
+		name := pathName(cfg.name, i, len(paths))
+		doc.Routes = append(doc.Routes, newRoute(name, path))
+		if !cfg.noTracks {
+			doc.Tracks = append(doc.Tracks, newTrack(name, path))
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newRoute(name string, path *go_huawei.Path) route {
+	r := route{Name: name, Desc: pathDescription(path)}
+
+	for _, step := range path.Steps {
+		p := newPoint(step.StartLocation)
+		p.Name = step.Instruction
+		if p.Name == "" {
+			p.Name = step.RoadName
+		}
+		p.Desc = step.DistanceText
+		p.Type = string(step.Action)
+		r.Points = append(r.Points, p)
+	}
+
+	end := newPoint(path.EndLocation)
+	end.Name = path.EndAddress
+	end.Type = string(go_huawei.End)
+	r.Points = append(r.Points, end)
+
+	return r
+}
+
+func newTrack(name string, path *go_huawei.Path) track {
+	segment := trackSegment{}
+	for _, c := range go_huawei.RemoveDuplicates(path.Overview()) {
+		segment.Points = append(segment.Points, newPoint(c))
+	}
+
+	return track{Name: name, Segments: []trackSegment{segment}}
+}
+
+func newPoint(c go_huawei.Coordinate) point {
+	return point{Lat: formatFloat(c.Lat), Lon: formatFloat(c.Lng)}
+}
+
+func pathName(name string, i, count int) string {
+	if count == 1 && name != "" {
+		return name
+	}
+
+	if name == "" {
+		name = "Route"
+	}
+
+	return name + " " + strconv.Itoa(i+1)
+}
+
+func pathDescription(path *go_huawei.Path) string {
+	switch {
+	case path.DistanceText != "" && path.DurationText != "":
+		return path.DistanceText + ", " + path.DurationText
+	case path.DistanceText != "":
+		return path.DistanceText
+	}
+
+	return path.DurationText
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
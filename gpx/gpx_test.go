@@ -0,0 +1,146 @@
+package gpx_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/gpx"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+func TestWriteAndReadTrack(t *testing.T) {
+	routes := huaweitest.NewRouter().Route(&go_huawei.DirectionsRequest{
+		Origin:      &go_huawei.Coordinate{Lat: 48.8566, Lng: 2.3522},
+		Destination: &go_huawei.Coordinate{Lat: 48.8738, Lng: 2.2950},
+	})
+	path := &routes[0].Paths[0]
+
+	var buf bytes.Buffer
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	if err := gpx.WritePath(&buf, path, gpx.WithName("Louvre to Étoile"), gpx.WithTime(created)); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<gpx version="1.1" creator="go-huawei" xmlns="http://www.topografix.com/GPX/1/1"`,
+		`<name>Louvre to Étoile</name>`,
+		`<time>2024-05-01T10:00:00Z</time>`,
+		`<rtept lat="48.8566" lon="2.3522">`,
+		`<type>end</type>`,
+		`<desc>` + path.DistanceText + `, ` + path.DurationText + `</desc>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document lacks %s:\n%s", want, doc)
+		}
+	}
+	if n := strings.Count(doc, "<rtept "); n != len(path.Steps)+1 {
+		t.Errorf("%d route points, want one per step and the end", n)
+	}
+
+	points, err := gpx.ReadTrack(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overview := go_huawei.RemoveDuplicates(path.Overview())
+	coords := gpx.Coordinates(points)
+	if len(coords) != len(overview) {
+		t.Fatalf("read %d track points, want %d", len(coords), len(overview))
+	}
+	for i := range coords {
+		if coords[i] != overview[i] {
+			t.Fatalf("track point %d = %v, want %v", i, coords[i], overview[i])
+		}
+	}
+}
+
+func TestWriteRoutesNames(t *testing.T) {
+	router := huaweitest.NewRouter()
+	routes := router.Route(&go_huawei.DirectionsRequest{
+		Origin:       &go_huawei.Coordinate{Lat: 1, Lng: 1},
+		Destination:  &go_huawei.Coordinate{Lat: 1.01, Lng: 1.01},
+		Alternatives: true,
+	})
+
+	var buf bytes.Buffer
+	if err := gpx.WriteRoutes(&buf, routes, gpx.WithoutTracks(), gpx.WithCreator("test")); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+
+	if !strings.Contains(doc, `creator="test"`) || strings.Contains(doc, "<trk>") || strings.Contains(doc, "<metadata>") {
+		t.Errorf("unexpected document:\n%s", doc)
+	}
+	for _, name := range []string{"Route 1", "Route 2", "Route 3"} {
+		if !strings.Contains(doc, "<name>"+name+"</name>") {
+			t.Errorf("document lacks route %q", name)
+		}
+	}
+
+	if _, err := gpx.ReadTrack(&buf); !errors.Is(err, gpx.ErrNoTrackPoints) {
+		t.Errorf("ReadTrack without tracks = %v, want ErrNoTrackPoints", err)
+	}
+}
+
+func TestReadTrackGPX10(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<gpx version="1.0" creator="eTrex" xmlns="http://www.topografix.com/GPX/1/0">
+  <trk>
+    <trkseg>
+      <trkpt lat="46.57608" lon="8.89241"><ele>2376</ele><time>2007-10-14T10:09:57Z</time></trkpt>
+      <trkpt lat=" 46.57619 " lon="8.89277"/>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="-46.5763" lon="-8.8930"><time>2007-10-14T12:10:11+02:00</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+	points, err := gpx.ReadTrack(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []gpx.TrackPoint{
+		{Location: go_huawei.Coordinate{Lat: 46.57608, Lng: 8.89241}, Elevation: 2376, Time: time.Date(2007, 10, 14, 10, 9, 57, 0, time.UTC)},
+		{Location: go_huawei.Coordinate{Lat: 46.57619, Lng: 8.89277}},
+		{Location: go_huawei.Coordinate{Lat: -46.5763, Lng: -8.893}, Time: time.Date(2007, 10, 14, 10, 10, 11, 0, time.UTC)},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("read %d points, want %d", len(points), len(want))
+	}
+	for i := range want {
+		got := points[i]
+		if got.Location != want[i].Location || got.Elevation != want[i].Elevation || !got.Time.Equal(want[i].Time) {
+			t.Errorf("point %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestReadTrackErrors(t *testing.T) {
+	for _, test := range []struct {
+		point string
+		err   string
+	}{
+		{`<trkpt lat="north" lon="8"/>`, `invalid lat "north"`},
+		{`<trkpt lat="46" lon=""/>`, `invalid lon ""`},
+		{`<trkpt lat="95" lon="8"/>`, "latitude 95 out of range"},
+		{`<trkpt lat="46" lon="8"><ele>high</ele></trkpt>`, `invalid ele "high"`},
+		{`<trkpt lat="46" lon="8"><time>yesterday</time></trkpt>`, `invalid time "yesterday"`},
+	} {
+		doc := `<gpx><trk><trkseg>` + test.point + `</trkseg></trk></gpx>`
+		if _, err := gpx.ReadTrack(strings.NewReader(doc)); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("ReadTrack(%s) = %v, want %q", test.point, err, test.err)
+		}
+	}
+
+	if _, err := gpx.ReadTrack(strings.NewReader("<gpx>")); err == nil || !strings.HasPrefix(err.Error(), "gpx: ") {
+		t.Errorf("ReadTrack of truncated XML = %v", err)
+	}
+}
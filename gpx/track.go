@@ -0,0 +1,95 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+// ErrNoTrackPoints is returned by ReadTrack for a document without track
+// points.
+var ErrNoTrackPoints = errors.New("gpx: no track points")
+
+// TrackPoint is a recorded GPS position.
+type TrackPoint struct {
+	Location go_huawei.Coordinate
+	// Time of the fix; zero when the point has no timestamp.
+	Time time.Time
+	// Elevation in metres; zero when the point has no elevation.
+	Elevation float64
+}
+
+// ReadTrack reads the track points of a GPX 1.0 or 1.1 document, in document
+// order across all tracks and segments.
+func ReadTrack(r io.Reader) ([]TrackPoint, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("gpx: %w", err)
+	}
+
+	var points []TrackPoint
+	for _, trk := range doc.Tracks {
+		for _, segment := range trk.Segments {
+			for i, p := range segment.Points {
+				point, err := parsePoint(p)
+				if err != nil {
+					return nil, fmt.Errorf("gpx: track point %d: %w", i, err)
+				}
+				points = append(points, point)
+			}
+		}
+	}
+
+	if len(points) == 0 {
+		return nil, ErrNoTrackPoints
+	}
+
+	return points, nil
+}
+
+// Coordinates returns the locations of the points.
+func Coordinates(points []TrackPoint) []go_huawei.Coordinate {
+	coords := make([]go_huawei.Coordinate, len(points))
+	for i, p := range points {
+		coords[i] = p.Location
+	}
+
+	return coords
+}
+
+func parsePoint(p point) (TrackPoint, error) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(p.Lat), 64)
+	if err != nil {
+		return TrackPoint{}, fmt.Errorf("invalid lat %q", p.Lat)
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(p.Lon), 64)
+	if err != nil {
+		return TrackPoint{}, fmt.Errorf("invalid lon %q", p.Lon)
+	}
+
+	point := TrackPoint{Location: go_huawei.Coordinate{Lat: lat, Lng: lng}}
+	if err := point.Location.Validate(); err != nil {
+		return TrackPoint{}, err
+	}
+
+	if s := strings.TrimSpace(p.Elevation); s != "" {
+		if point.Elevation, err = strconv.ParseFloat(s, 64); err != nil {
+			return TrackPoint{}, fmt.Errorf("invalid ele %q", p.Elevation)
+		}
+	}
+
+	if s := strings.TrimSpace(p.Time); s != "" {
+		if point.Time, err = time.Parse(time.RFC3339, s); err != nil {
+			return TrackPoint{}, fmt.Errorf("invalid time %q", p.Time)
+		}
+	}
+
+	return point, nil
+}
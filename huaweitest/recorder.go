@@ -0,0 +1,379 @@
+// Package huaweitest provides utilities for testing code that uses the go-huawei
+// client without access to the live Map Kit service.
+package huaweitest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Mode selects whether a Recorder talks to the network or replays a cassette.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and never touches the network.
+	ModeReplay = Mode(0)
+	// ModeRecord forwards requests to the real service and records them.
+	ModeRecord = Mode(1)
+	// ModeReplayOrRecord replays the cassette if it exists and records a new one otherwise.
+	ModeReplayOrRecord = Mode(2)
+)
+
+const redactedValue = "REDACTED"
+
+// ErrNoInteraction is returned by a replaying Recorder when the cassette has no
+// interaction matching the request.
+var ErrNoInteraction = errors.New("huaweitest: no recorded interaction matches request")
+
+// scrubbedHeaders are removed from recorded requests and responses.
+var scrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Cassette is the on-disk representation of recorded interactions.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Request  InteractionRequest  `json:"request"`
+	Response InteractionResponse `json:"response"`
+
+	replayed bool
+}
+
+// InteractionRequest is a recorded request with the API key scrubbed. JSON bodies
+// are stored as JSON, anything else as text.
+type InteractionRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   string          `json:"body,omitempty"`
+}
+
+// InteractionResponse is a recorded response. Gzip encoded bodies are stored
+// decoded.
+type InteractionResponse struct {
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header,omitempty"`
+	JSON       json.RawMessage `json:"json,omitempty"`
+	Body       string          `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records real request/response pairs to a
+// cassette file and replays them offline. Pass Recorder.HTTPClient() to
+// go_huawei.WithHTTPClient: the client wraps the recorder in its own transport,
+// so the recorded requests are exactly what the client sends.
+type Recorder struct {
+	path string
+	mode Mode
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// RecorderOption is the type of constructor options for NewRecorder(...).
+type RecorderOption func(*Recorder)
+
+// WithRecorderTransport configures the transport used to reach the real service
+// while recording. Default is http.DefaultTransport.
+func WithRecorderTransport(base http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.base = base
+	}
+}
+
+// NewRecorder creates a Recorder backed by the cassette file at path. In replay
+// mode the cassette must exist.
+func NewRecorder(path string, mode Mode, options ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		base:     http.DefaultTransport,
+		cassette: &Cassette{},
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	if r.mode == ModeReplayOrRecord {
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("huaweitest: load cassette: %w", err)
+		}
+
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("huaweitest: decode cassette %s: %w", path, err)
+		}
+	}
+
+	return r, nil
+}
+
+// Mode returns the effective mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// HTTPClient returns an http.Client that sends its requests through the recorder.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays a single request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+
+	return r.record(req, body)
+}
+
+// Save writes the recorded interactions to the cassette file. It is a no-op in
+// replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("huaweitest: encode cassette: %w", err)
+	}
+
+	if dir := filepath.Dir(r.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("huaweitest: save cassette: %w", err)
+		}
+	}
+
+	if err := ioutil.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("huaweitest: save cassette: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := r.base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	respBody, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	header := scrubHeader(resp.Header)
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+
+	interaction := &Interaction{
+		Request: InteractionRequest{
+			Method: req.Method,
+			URL:    scrubURL(req),
+			Header: scrubHeader(req.Header),
+		},
+		Response: InteractionResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+		},
+	}
+	interaction.Request.JSON, interaction.Request.Body = splitBody(body)
+	interaction.Response.JSON, interaction.Response.Body = splitBody(respBody)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return interaction.Response.toHTTP(req), nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := matchKey(req.Method, req.URL.Path, body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Prefer interactions that have not been replayed yet so repeated identical
+	// requests get their responses in recorded order.
+	var fallback *Interaction
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.Request.matchKey() != key {
+			continue
+		}
+
+		if !interaction.replayed {
+			interaction.replayed = true
+			return interaction.Response.toHTTP(req), nil
+		}
+
+		if fallback == nil {
+			fallback = interaction
+		}
+	}
+
+	if fallback != nil {
+		return fallback.Response.toHTTP(req), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.Path)
+}
+
+func (i *InteractionRequest) matchKey() string {
+	path := i.URL
+	if u, err := url.Parse(i.URL); err == nil {
+		path = u.Path
+	}
+
+	body := []byte(i.JSON)
+	if len(body) == 0 {
+		body = []byte(i.Body)
+	}
+
+	return matchKey(i.Method, path, body)
+}
+
+func (i *InteractionResponse) toHTTP(req *http.Request) *http.Response {
+	body := []byte(i.JSON)
+	if len(body) == 0 {
+		body = []byte(i.Body)
+	}
+
+	header := i.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// matchKey identifies a request by method, path and normalized JSON body.
+func matchKey(method, path string, body []byte) string {
+	return method + " " + path + " " + string(normalizeJSON(body))
+}
+
+// normalizeJSON re-encodes a JSON document so that formatting and key order do
+// not affect matching. Non-JSON bodies are returned unchanged.
+func normalizeJSON(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return normalized
+}
+
+func splitBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+
+	if json.Valid(body) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err == nil {
+			return buf.Bytes(), ""
+		}
+	}
+
+	return nil, string(body)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	defer req.Body.Close()
+
+	return ioutil.ReadAll(req.Body)
+}
+
+func scrubURL(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	if q.Get("key") != "" {
+		q.Set("key", redactedValue)
+		u.RawQuery = q.Encode()
+	}
+
+	return u.String()
+}
+
+func scrubHeader(h http.Header) http.Header {
+	scrubbed := h.Clone()
+	if scrubbed == nil {
+		return nil
+	}
+
+	for _, name := range scrubbedHeaders {
+		scrubbed.Del(name)
+	}
+
+	return scrubbed
+}
@@ -0,0 +1,142 @@
+package huaweitest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write([]byte(`{"echo":` + string(body) + `,"call":` + string(rune('0'+calls)) + `}`))
+		_ = gz.Close()
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Set-Cookie", "session=1")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "directions.json")
+
+	recorder, err := NewRecorder(path, ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Mode() != ModeRecord {
+		t.Fatalf("mode = %v without a cassette, want ModeRecord", recorder.Mode())
+	}
+
+	for i := 0; i < 2; i++ {
+		got := post(t, recorder.HTTPClient(), upstream.URL+"/route?key=secret", `{"a": 1, "b": 2}`)
+		if want := `{"call":` + string(rune('1'+i)) + `,"echo":{"a":1,"b":2}}`; got != want {
+			t.Fatalf("recorded response %d = %s, want %s", i, got, want)
+		}
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cassette := string(data); strings.Contains(cassette, "secret") || strings.Contains(cassette, "session=1") {
+		t.Errorf("cassette leaks the key or cookies:\n%s", cassette)
+	}
+
+	replayer, err := NewRecorder(path, ModeReplayOrRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayer.Mode() != ModeReplay {
+		t.Fatalf("mode = %v with a cassette, want ModeReplay", replayer.Mode())
+	}
+
+	// Key order and formatting of the body do not matter; identical requests
+	// get their responses in recorded order, then the first one again.
+	for i, want := range []string{"1", "2", "1"} {
+		got := post(t, replayer.HTTPClient(), "http://offline.invalid/route?key=other", `{"b":2,"a":1}`)
+		if !strings.HasPrefix(got, `{"call":`+want+`,`) {
+			t.Errorf("replayed response %d = %s, want call %s", i, got, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("upstream called %d times, want 2", calls)
+	}
+
+	_, err = replayer.HTTPClient().Post("http://offline.invalid/other", "application/json", strings.NewReader(`{}`))
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("unmatched request error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorderReplayMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	if err == nil {
+		t.Fatal("want an error for a missing cassette")
+	}
+}
+
+func TestNormalizeJSON(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{ "b": [1, 2], "a": null }`, `{"a":null,"b":[1,2]}`},
+		{"not json", "not json"},
+		{"  ", ""},
+	}
+
+	for _, test := range tests {
+		if got := string(normalizeJSON([]byte(test.in))); got != test.want {
+			t.Errorf("normalizeJSON(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestSplitBody(t *testing.T) {
+	raw, text := splitBody([]byte("{\n  \"a\": 1\n}"))
+	if string(raw) != `{"a":1}` || text != "" {
+		t.Errorf("JSON body split into %s and %q", raw, text)
+	}
+
+	raw, text = splitBody([]byte("Not Found"))
+	if raw != nil || text != "Not Found" {
+		t.Errorf("text body split into %s and %q", raw, text)
+	}
+
+	if _, err := json.Marshal(Interaction{}); err != nil {
+		t.Errorf("empty interaction does not encode: %v", err)
+	}
+}
+
+func post(t *testing.T, client *http.Client, url, body string) string {
+	t.Helper()
+
+	resp, err := client.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(normalizeJSON(data))
+}
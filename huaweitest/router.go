@@ -0,0 +1,316 @@
+package huaweitest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+// Default speed profiles, in metres per second.
+var defaultSpeeds = map[go_huawei.RouteService]float64{
+	go_huawei.RouteServiceDriving:   50 / 3.6,
+	go_huawei.RouteServiceBicycling: 15 / 3.6,
+	go_huawei.RouteServiceWalking:   5 / 3.6,
+}
+
+// Traffic factors applied to driving durations per TrafficMode.
+var trafficFactors = map[go_huawei.TrafficMode]float64{
+	go_huawei.TrafficModeBestGuess:   1.15,
+	go_huawei.TrafficModePessimistic: 1.4,
+	go_huawei.TrafficModeOptimistic:  1.0,
+}
+
+// Default step length and polyline vertex spacing, in metres.
+const (
+	defaultStepLength   = 1000
+	defaultPointSpacing = 100
+)
+
+// Router is a deterministic offline routing engine. It connects origin,
+// waypoints and destination with great-circle legs, splits them into steps with
+// maneuvers and derives distances and durations from a per RouteService speed
+// profile. The same request always produces the same routes.
+type Router struct {
+	// StepLength is the maximum length of a step, in metres. Zero or less
+	// means 1 km.
+	StepLength float64
+	// PointSpacing is the maximum distance between polyline vertices, in
+	// metres. Zero or less means 100 m.
+	PointSpacing float64
+	// Speeds maps a RouteService to its speed, in metres per second. Requests
+	// without a RouteService use the driving speed.
+	Speeds map[go_huawei.RouteService]float64
+}
+
+// NewRouter returns a Router with 1 km steps, 100 m vertex spacing and the
+// default speed profiles.
+func NewRouter() *Router {
+	speeds := make(map[go_huawei.RouteService]float64, len(defaultSpeeds))
+	for service, speed := range defaultSpeeds {
+		speeds[service] = speed
+	}
+
+	return &Router{
+		StepLength:   defaultStepLength,
+		PointSpacing: defaultPointSpacing,
+		Speeds:       speeds,
+	}
+}
+
+// Responder returns a Responder answering routeService requests with routes
+// computed by the router, for use with WithResponder or Server.SetResponder.
+func (r *Router) Responder() Responder {
+	return func(req *Request) Response {
+		return Response{Routes: r.Route(req.Directions)}
+	}
+}
+
+// Route computes the routes for a request. When alternatives are requested and
+// there are no waypoints, two detours around the direct route are added.
+func (r *Router) Route(req *go_huawei.DirectionsRequest) []go_huawei.Route {
+	if req == nil || req.Origin == nil || req.Destination == nil {
+		return nil
+	}
+
+	waypoints := make([]go_huawei.Coordinate, 0, len(req.Waypoints))
+	for _, waypoint := range req.Waypoints {
+		if waypoint != nil {
+			waypoints = append(waypoints, *waypoint)
+		}
+	}
+	if req.Optimize {
+		waypoints = orderByProximity(*req.Origin, waypoints)
+	}
+
+	points := append([]go_huawei.Coordinate{*req.Origin}, waypoints...)
+	points = append(points, *req.Destination)
+
+	routes := []go_huawei.Route{r.route(req, points)}
+
+	if req.Alternatives && len(waypoints) == 0 {
+		origin, destination := *req.Origin, *req.Destination
+		length := origin.DistanceTo(destination)
+		mid := origin.Midpoint(destination)
+		heading := origin.BearingTo(destination)
+
+		for _, offset := range []float64{0.15, -0.25} {
+			side := heading + 90
+			if offset < 0 {
+				side = heading - 90
+			}
+
+			detour := mid.Destination(side, math.Abs(offset)*length)
+			routes = append(routes, r.route(req, []go_huawei.Coordinate{origin, detour, destination}))
+		}
+	}
+
+	return routes
+}
+
+func (r *Router) route(req *go_huawei.DirectionsRequest, points []go_huawei.Coordinate) go_huawei.Route {
+	speed := r.speed(req.RouteService)
+	trafficFactor := 1.0
+	if req.RouteService == "" || req.RouteService == go_huawei.RouteServiceDriving {
+		if factor, ok := trafficFactors[req.TrafficMode]; ok {
+			trafficFactor = factor
+		}
+	}
+
+	path := go_huawei.Path{
+		StartLocation: points[0],
+		EndLocation:   points[len(points)-1],
+	}
+
+	previousBearing := math.NaN()
+	for leg := 1; leg < len(points); leg++ {
+		from, to := points[leg-1], points[leg]
+		legLength := from.DistanceTo(to)
+
+		steps := int(math.Ceil(legLength / r.stepLength()))
+		if steps < 1 {
+			steps = 1
+		}
+
+		for i := 0; i < steps; i++ {
+			start := from.Interpolate(to, float64(i)/float64(steps))
+			end := from.Interpolate(to, float64(i+1)/float64(steps))
+			stepBearing := start.BearingTo(end)
+
+			action := go_huawei.Straight
+			if i == 0 && !math.IsNaN(previousBearing) {
+				action = turnAction(stepBearing - previousBearing)
+			}
+			previousBearing = stepBearing
+
+			step := r.step(start, end, legLength/float64(steps), speed, action, leg)
+			path.Steps = append(path.Steps, step)
+			path.Distance += step.Distance
+			path.Duration += step.Duration
+		}
+	}
+
+	if len(path.Steps) > 0 {
+		last := &path.Steps[len(path.Steps)-1]
+		last.Action = go_huawei.End
+		last.Instruction = fmt.Sprintf("Arrive at destination on %s", last.RoadName)
+	}
+
+	path.DurationInTraffic = math.Round(path.Duration * trafficFactor)
+	path.DistanceText = distanceText(path.Distance)
+	path.DurationText = durationText(path.Duration)
+	path.DurationInTrafficText = durationText(path.DurationInTraffic)
+
+	return go_huawei.Route{
+		Paths:  []go_huawei.Path{path},
+		Bounds: go_huawei.NewCoordinateBounds(path.Overview()),
+	}
+}
+
+func (r *Router) step(start, end go_huawei.Coordinate, length, speed float64, action go_huawei.Action, leg int) go_huawei.Step {
+	vertices := int(math.Ceil(length / r.pointSpacing()))
+	if vertices < 1 {
+		vertices = 1
+	}
+
+	polyline := make([]go_huawei.Coordinate, 0, vertices+1)
+	for i := 0; i <= vertices; i++ {
+		polyline = append(polyline, start.Interpolate(end, float64(i)/float64(vertices)))
+	}
+
+	roadName := fmt.Sprintf("Road %d", leg)
+	stepDistance := math.Round(length)
+	duration := math.Round(length / speed)
+	heading := start.BearingTo(end)
+
+	return go_huawei.Step{
+		Duration:      duration,
+		DurationText:  durationText(duration),
+		Distance:      stepDistance,
+		DistanceText:  distanceText(stepDistance),
+		StartLocation: start,
+		EndLocation:   end,
+		Orientation:   int64(math.Round(heading)) % 360,
+		Action:        action,
+		Instruction:   instruction(action, roadName, heading),
+		Polyline:      polyline,
+		RoadName:      roadName,
+	}
+}
+
+func (r *Router) stepLength() float64 {
+	if r.StepLength > 0 {
+		return r.StepLength
+	}
+
+	return defaultStepLength
+}
+
+func (r *Router) pointSpacing() float64 {
+	if r.PointSpacing > 0 {
+		return r.PointSpacing
+	}
+
+	return defaultPointSpacing
+}
+
+func (r *Router) speed(service go_huawei.RouteService) float64 {
+	if service == "" {
+		service = go_huawei.RouteServiceDriving
+	}
+
+	if speed, ok := r.Speeds[service]; ok && speed > 0 {
+		return speed
+	}
+
+	return defaultSpeeds[go_huawei.RouteServiceDriving]
+}
+
+// orderByProximity orders waypoints greedily by distance, starting at origin.
+func orderByProximity(origin go_huawei.Coordinate, waypoints []go_huawei.Coordinate) []go_huawei.Coordinate {
+	remaining := append([]go_huawei.Coordinate(nil), waypoints...)
+	ordered := make([]go_huawei.Coordinate, 0, len(waypoints))
+
+	current := origin
+	for len(remaining) > 0 {
+		nearest := 0
+		for i := range remaining {
+			if current.DistanceTo(remaining[i]) < current.DistanceTo(remaining[nearest]) {
+				nearest = i
+			}
+		}
+
+		current = remaining[nearest]
+		ordered = append(ordered, current)
+		remaining = append(remaining[:nearest], remaining[nearest+1:]...)
+	}
+
+	return ordered
+}
+
+// turnAction classifies a change of heading, in degrees, as a maneuver.
+func turnAction(delta float64) go_huawei.Action {
+	delta = math.Mod(delta+540, 360) - 180
+
+	switch {
+	case delta <= -170:
+		return go_huawei.UTurnLeft
+	case delta <= -120:
+		return go_huawei.TurnSharpLeft
+	case delta <= -45:
+		return go_huawei.TurnLeft
+	case delta <= -20:
+		return go_huawei.TurnSlightLeft
+	case delta < 20:
+		return go_huawei.Straight
+	case delta < 45:
+		return go_huawei.TurnSlightRight
+	case delta < 120:
+		return go_huawei.TurnRight
+	case delta < 170:
+		return go_huawei.TurnSharpRight
+	default:
+		return go_huawei.UTurnRight
+	}
+}
+
+var compassPoints = []string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"}
+
+func instruction(action go_huawei.Action, roadName string, heading float64) string {
+	switch action {
+	case go_huawei.TurnLeft:
+		return fmt.Sprintf("Turn left onto %s", roadName)
+	case go_huawei.TurnRight:
+		return fmt.Sprintf("Turn right onto %s", roadName)
+	case go_huawei.TurnSlightLeft:
+		return fmt.Sprintf("Turn slightly left onto %s", roadName)
+	case go_huawei.TurnSlightRight:
+		return fmt.Sprintf("Turn slightly right onto %s", roadName)
+	case go_huawei.TurnSharpLeft:
+		return fmt.Sprintf("Turn sharp left onto %s", roadName)
+	case go_huawei.TurnSharpRight:
+		return fmt.Sprintf("Turn sharp right onto %s", roadName)
+	case go_huawei.UTurnLeft, go_huawei.UTurnRight:
+		return fmt.Sprintf("Make a U-turn onto %s", roadName)
+	default:
+		point := compassPoints[int(math.Mod(heading+22.5+360, 360)/45)%len(compassPoints)]
+		return fmt.Sprintf("Head %s on %s", point, roadName)
+	}
+}
+
+func distanceText(meters float64) string {
+	if meters < 1000 {
+		return fmt.Sprintf("%.0fm", meters)
+	}
+
+	return fmt.Sprintf("%.1fkm", meters/1000)
+}
+
+func durationText(seconds float64) string {
+	minutes := int(math.Ceil(seconds / 60))
+	if minutes < 60 {
+		return fmt.Sprintf("%dmin", minutes)
+	}
+
+	return fmt.Sprintf("%dh %dmin", minutes/60, minutes%60)
+}
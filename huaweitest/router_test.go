@@ -0,0 +1,123 @@
+package huaweitest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+func TestRouterZeroValue(t *testing.T) {
+	req := &go_huawei.DirectionsRequest{
+		Origin:      &go_huawei.Coordinate{Lat: 52.2297, Lng: 21.0122},
+		Destination: &go_huawei.Coordinate{Lat: 52.2400, Lng: 21.0500},
+	}
+
+	var zero Router
+	negative := Router{StepLength: -1, PointSpacing: math.NaN()}
+
+	want := NewRouter().Route(req)
+	for _, r := range []*Router{&zero, &negative} {
+		if got := r.Route(req); !reflect.DeepEqual(got, want) {
+			t.Errorf("Router%+v routes differ from NewRouter's", *r)
+		}
+	}
+}
+
+func TestRouterDeterministic(t *testing.T) {
+	req := &go_huawei.DirectionsRequest{
+		Origin:       &go_huawei.Coordinate{Lat: 48.8566, Lng: 2.3522},
+		Destination:  &go_huawei.Coordinate{Lat: 48.8049, Lng: 2.1204},
+		Alternatives: true,
+	}
+
+	first := NewRouter().Route(req)
+	second := NewRouter().Route(req)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("the same request produced different routes")
+	}
+	if len(first) != 3 {
+		t.Fatalf("got %d routes with alternatives, want 3", len(first))
+	}
+
+	direct := first[0].Paths[0]
+	for i, alternative := range first[1:] {
+		if alternative.Paths[0].Distance <= direct.Distance {
+			t.Errorf("alternative %d is %v m, not longer than the direct %v m", i+1, alternative.Paths[0].Distance, direct.Distance)
+		}
+	}
+}
+
+func TestRouterSteps(t *testing.T) {
+	r := NewRouter()
+	r.StepLength = 500
+	r.PointSpacing = 50
+
+	// East, then north: a left turn at the waypoint.
+	req := &go_huawei.DirectionsRequest{
+		Origin:      &go_huawei.Coordinate{Lat: 0, Lng: 0},
+		Waypoints:   []*go_huawei.Coordinate{{Lat: 0, Lng: 0.018}},
+		Destination: &go_huawei.Coordinate{Lat: 0.018, Lng: 0.018},
+	}
+
+	path := r.Route(req)[0].Paths[0]
+	if len(path.Steps) != 10 {
+		t.Fatalf("got %d steps, want 10 of at most 500 m", len(path.Steps))
+	}
+
+	var distance float64
+	for i, step := range path.Steps {
+		distance += step.Distance
+		if step.Distance > 500 {
+			t.Errorf("step %d is %v m long", i, step.Distance)
+		}
+		if n := len(step.Polyline); n < 2 || step.Polyline[0] != step.StartLocation || step.Polyline[n-1] != step.EndLocation {
+			t.Errorf("step %d polyline does not run from start to end", i)
+		}
+	}
+	if distance != path.Distance {
+		t.Errorf("steps add up to %v m, path is %v m", distance, path.Distance)
+	}
+
+	tests := []struct {
+		step        int
+		action      go_huawei.Action
+		orientation int64
+	}{
+		{0, go_huawei.Straight, 90},
+		{4, go_huawei.Straight, 90},
+		{5, go_huawei.TurnLeft, 0},
+		{9, go_huawei.End, 0},
+	}
+	for _, test := range tests {
+		step := path.Steps[test.step]
+		if step.Action != test.action || step.Orientation != test.orientation {
+			t.Errorf("step %d = %s heading %d, want %s heading %d", test.step, step.Action, step.Orientation, test.action, test.orientation)
+		}
+	}
+}
+
+func TestTurnAction(t *testing.T) {
+	tests := []struct {
+		delta float64
+		want  go_huawei.Action
+	}{
+		{0, go_huawei.Straight},
+		{-19, go_huawei.Straight},
+		{30, go_huawei.TurnSlightRight},
+		{-30, go_huawei.TurnSlightLeft},
+		{90, go_huawei.TurnRight},
+		{270, go_huawei.TurnLeft},
+		{135, go_huawei.TurnSharpRight},
+		{-135, go_huawei.TurnSharpLeft},
+		{175, go_huawei.UTurnRight},
+		{-178, go_huawei.UTurnLeft},
+	}
+
+	for _, test := range tests {
+		if got := turnAction(test.delta); got != test.want {
+			t.Errorf("turnAction(%v) = %s, want %s", test.delta, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package huaweitest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+const routeServicePath = "/mapApi/v1/routeService/"
+
+// Response is a scripted reply of the fake Map Kit server. The zero value is a
+// successful response without routes.
+type Response struct {
+	// Routes returned in a successful response.
+	Routes []go_huawei.Route
+	// ReturnCode and ReturnDesc default to ReturnCodeOK and ReturnDescOK.
+	ReturnCode go_huawei.ReturnCode
+	ReturnDesc go_huawei.ReturnDesc
+
+	// StatusCode is the HTTP status of the response. Default is 200.
+	StatusCode int
+	// Body replaces the JSON body built from the fields above when set.
+	Body []byte
+	// Latency delays the response. The delay is cut short if the client gives up.
+	Latency time.Duration
+	// Gzip compresses the body and sets the Content-Encoding header.
+	Gzip bool
+}
+
+// Request is a request received by the fake server.
+type Request struct {
+	Method       string
+	Path         string
+	Key          string
+	RouteService go_huawei.RouteService
+	Header       http.Header
+	Body         []byte
+
+	// Directions is the decoded body of a routeService request, nil if the body
+	// could not be decoded.
+	Directions *go_huawei.DirectionsRequest
+}
+
+// Responder computes the reply to a request that has no scripted response queued.
+type Responder func(req *Request) Response
+
+// Server is a fake Huawei Map Kit server built on httptest. Point a client to it
+// with go_huawei.WithBaseURL(server.URL).
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	queue     []Response
+	responder Responder
+	requests  []Request
+}
+
+// ServerOption is the type of constructor options for NewServer(...).
+type ServerOption func(*Server)
+
+// WithResponder configures how the server replies once its queue of scripted
+// responses is empty. Default is a successful response without routes.
+func WithResponder(responder Responder) ServerOption {
+	return func(s *Server) {
+		s.responder = responder
+	}
+}
+
+// NewServer starts a fake Map Kit server implementing the routeService
+// endpoints. The caller must Close it when done.
+func NewServer(options ...ServerOption) *Server {
+	s := &Server{
+		responder: func(_ *Request) Response { return Response{} },
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Enqueue scripts the next responses of the server, one per request, in order.
+func (s *Server) Enqueue(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queue = append(s.queue, responses...)
+}
+
+// SetResponder replaces the responder used once the queue is empty.
+func (s *Server) SetResponder(responder Responder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responder = responder
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]Request, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// Reset drops queued responses and received requests.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queue = nil
+	s.requests = nil
+}
+
+// QuotaExceeded returns a response reporting that the API call quota is used up
+// (ReturnCode 010006).
+func QuotaExceeded() Response {
+	return Response{
+		ReturnCode: go_huawei.ReturnCodeAPICallQuotaUsedUp,
+		ReturnDesc: go_huawei.ReturnDescOverQueryLimit,
+	}
+}
+
+// InternalError returns a response reporting an internal service error
+// (ReturnCode 110).
+func InternalError() Response {
+	return Response{
+		ReturnCode: go_huawei.ReturnCodeInternalServiceError,
+		ReturnDesc: go_huawei.ReturnDescUnknownError,
+	}
+}
+
+// HTTPError returns a response with the given HTTP status and a plain text body.
+func HTTPError(statusCode int) Response {
+	return Response{
+		StatusCode: statusCode,
+		Body:       []byte(http.StatusText(statusCode)),
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	req := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Key:    r.URL.Query().Get("key"),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	if strings.HasPrefix(r.URL.Path, routeServicePath) {
+		req.RouteService = go_huawei.RouteService(strings.TrimPrefix(r.URL.Path, routeServicePath))
+
+		directions := &go_huawei.DirectionsRequest{}
+		if err := json.Unmarshal(body, directions); err == nil {
+			directions.RouteService = req.RouteService
+			req.Directions = directions
+		}
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	switch {
+	case !strings.HasPrefix(r.URL.Path, routeServicePath):
+		http.NotFound(w, r)
+		return
+	case r.Method != http.MethodPost:
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	queued := len(s.queue) > 0
+	var resp Response
+	if queued {
+		resp = s.queue[0]
+		s.queue = s.queue[1:]
+	}
+	responder := s.responder
+	s.mu.Unlock()
+
+	if !queued {
+		resp = validate(&req, responder)
+	}
+
+	s.write(w, r, resp)
+}
+
+// validate rejects requests the real service would reject and otherwise asks the
+// responder.
+func validate(req *Request, responder Responder) Response {
+	switch {
+	case req.Key == "":
+		return Response{ReturnCode: go_huawei.ReturnCodeInvalidAPI, ReturnDesc: go_huawei.ReturnDescRequestDenied}
+	case req.Directions == nil || req.Directions.Origin == nil || req.Directions.Destination == nil:
+		return Response{ReturnCode: go_huawei.ReturnCodeInvalidRequest, ReturnDesc: go_huawei.ReturnDescInvalidRequest}
+	}
+
+	switch req.RouteService {
+	case go_huawei.RouteServiceDriving, go_huawei.RouteServiceWalking, go_huawei.RouteServiceBicycling:
+	default:
+		return Response{ReturnCode: go_huawei.ReturnCodeRequestedURLIncorrect, ReturnDesc: go_huawei.ReturnDescNotFound}
+	}
+
+	return responder(req)
+}
+
+func (s *Server) write(w http.ResponseWriter, r *http.Request, resp Response) {
+	if resp.Latency > 0 {
+		timer := time.NewTimer(resp.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	body := resp.Body
+	if body == nil {
+		body = encodeResponse(resp)
+	}
+
+	contentType := "application/json"
+	if !json.Valid(body) {
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	if resp.Gzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write(body)
+		_ = gz.Close()
+
+		body = buf.Bytes()
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	statusCode := resp.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
+
+func encodeResponse(resp Response) []byte {
+	out := go_huawei.DirectionsResponse{
+		Routes: resp.Routes,
+		CommonResponse: go_huawei.CommonResponse{
+			ReturnCode: resp.ReturnCode,
+			ReturnDesc: resp.ReturnDesc,
+		},
+	}
+
+	if out.ReturnCode == "" {
+		out.ReturnCode = go_huawei.ReturnCodeOK
+	}
+	if out.ReturnDesc == "" {
+		if out.ReturnCode == go_huawei.ReturnCodeOK {
+			out.ReturnDesc = go_huawei.ReturnDescOK
+		} else {
+			out.ReturnDesc = go_huawei.ReturnDescUnknownError
+		}
+	}
+	if out.Routes == nil {
+		out.Routes = []go_huawei.Route{}
+	}
+
+	body, _ := json.Marshal(out)
+	return body
+}
@@ -0,0 +1,174 @@
+package huaweitest_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+func newClient(t *testing.T, server *huaweitest.Server) *go_huawei.Client {
+	t.Helper()
+
+	client, err := go_huawei.NewClient(go_huawei.WithAPIKey("test-key"), go_huawei.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+var kyivRequest = go_huawei.DirectionsRequest{
+	Origin:       &go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234},
+	Destination:  &go_huawei.Coordinate{Lat: 50.4010, Lng: 30.6520},
+	RouteService: go_huawei.RouteServiceWalking,
+}
+
+func TestServerScriptedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response huaweitest.Response
+		code     go_huawei.ReturnCode
+	}{
+		{"quota", huaweitest.QuotaExceeded(), go_huawei.ReturnCodeAPICallQuotaUsedUp},
+		{"internal", huaweitest.InternalError(), go_huawei.ReturnCodeInternalServiceError},
+		{"gzip", huaweitest.Response{ReturnCode: go_huawei.ReturnCodeRouteDataDoesNotExist, Gzip: true}, go_huawei.ReturnCodeRouteDataDoesNotExist},
+	}
+
+	server := huaweitest.NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server.Enqueue(test.response)
+
+			request := kyivRequest
+			_, err := client.Directions(context.Background(), &request)
+
+			var apiErr *go_huawei.GoHuaweiError
+			if !errors.As(err, &apiErr) || !apiErr.IsApiError {
+				t.Fatalf("err = %v, want an API error", err)
+			}
+			if apiErr.ReturnCode != test.code {
+				t.Errorf("ReturnCode = %s, want %s", apiErr.ReturnCode, test.code)
+			}
+		})
+	}
+}
+
+func TestServerHTTPError(t *testing.T) {
+	server := huaweitest.NewServer()
+	defer server.Close()
+	server.Enqueue(huaweitest.HTTPError(http.StatusServiceUnavailable))
+
+	request := kyivRequest
+	if _, err := newClient(t, server).Directions(context.Background(), &request); err == nil {
+		t.Fatal("want an error for a plain text 503 body")
+	}
+}
+
+func TestServerValidation(t *testing.T) {
+	server := huaweitest.NewServer()
+	defer server.Close()
+
+	tests := []struct {
+		path, body string
+		want       string
+	}{
+		{"/mapApi/v1/routeService/driving", `{}`, `"returnCode":"6"`},
+		{"/mapApi/v1/routeService/driving?key=k", `{"origin":{"lat":1,"lng":1}}`, `"returnCode":"010010"`},
+		{"/mapApi/v1/routeService/flying?key=k", `{"origin":{"lat":1,"lng":1},"destination":{"lat":2,"lng":2}}`, `"returnCode":"5"`},
+		{"/mapApi/v1/routeService/?key=k", `{"origin":{"lat":1,"lng":1},"destination":{"lat":2,"lng":2}}`, `"returnDesc":"NOT_FOUND"`},
+		{"/mapApi/v1/routeService/driving?key=k", `{"origin":{"lat":1,"lng":1},"destination":{"lat":2,"lng":2}}`, `"returnCode":"0"`},
+	}
+
+	for _, test := range tests {
+		resp, err := http.Post(server.URL+test.path, "application/json", strings.NewReader(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body := readAll(t, resp)
+		if !strings.Contains(body, test.want) {
+			t.Errorf("POST %s = %s, want %s", test.path, body, test.want)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/mapApi/v1/routeService/driving")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readAll(t, resp)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", resp.StatusCode)
+	}
+
+	requests := server.Requests()
+	if len(requests) != len(tests)+1 {
+		t.Fatalf("recorded %d requests, want %d", len(requests), len(tests)+1)
+	}
+	if got := requests[4]; got.Key != "k" || got.RouteService != go_huawei.RouteServiceDriving || got.Directions == nil {
+		t.Errorf("recorded request = %+v", got)
+	}
+
+	server.Reset()
+	if len(server.Requests()) != 0 {
+		t.Error("Reset kept the requests")
+	}
+}
+
+func TestServerLatencyHonoursContext(t *testing.T) {
+	server := huaweitest.NewServer()
+	defer server.Close()
+	server.Enqueue(huaweitest.Response{Latency: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	request := kyivRequest
+	start := time.Now()
+	if _, err := newClient(t, server).Directions(ctx, &request); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %s despite the deadline", elapsed)
+	}
+}
+
+func TestServerResponder(t *testing.T) {
+	route := go_huawei.Route{Paths: []go_huawei.Path{{Distance: 1234}}}
+	server := huaweitest.NewServer(huaweitest.WithResponder(func(req *huaweitest.Request) huaweitest.Response {
+		if req.Directions.Destination.Lat != kyivRequest.Destination.Lat {
+			return huaweitest.InternalError()
+		}
+		return huaweitest.Response{Routes: []go_huawei.Route{route}}
+	}))
+	defer server.Close()
+
+	request := kyivRequest
+	routes, err := newClient(t, server).Directions(context.Background(), &request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 || routes[0].Paths[0].Distance != 1234 {
+		t.Errorf("routes = %+v", routes)
+	}
+}
+
+func readAll(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
@@ -0,0 +1,346 @@
+// Package instructions builds turn-by-turn instructions in the user's language
+// from Step.Action, Orientation, RoadName and distance, for locales the API does
+// not serve. Bundles for en, uk, pl and zh are built in; others can be added
+// with Register.
+package instructions
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+// ErrUnsupportedLocale is returned by New for locales without a bundle.
+var ErrUnsupportedLocale = errors.New("instructions: unsupported locale")
+
+// ErrInvalidBundle is returned by Register for bundles missing a required
+// field.
+var ErrInvalidBundle = errors.New("instructions: invalid bundle")
+
+// Phrase is the text of a maneuver. Onto is used when the road name is known and
+// may contain {road}; Plain is used otherwise. Both may contain {exit} and
+// {direction}.
+type Phrase struct {
+	Plain string
+	Onto  string
+}
+
+// Bundle holds the texts and language rules of a locale. Locale, Actions and
+// Distance are required.
+type Bundle struct {
+	// Locale is the language tag, such as "en" or "uk".
+	Locale string
+
+	// Actions are the phrases of maneuvers; Default is used for actions
+	// missing from the map.
+	Actions map[go_huawei.Action]Phrase
+	Default Phrase
+	// EnterRoundabout is used for roundabouts with an unknown exit number.
+	EnterRoundabout Phrase
+	// Depart is the phrase of the first step, with {direction} the compass
+	// direction of its Orientation. Directions lists the eight compass
+	// directions clockwise from north.
+	Depart     Phrase
+	Directions [8]string
+
+	// Announcement wraps an instruction given ahead of the maneuver; it
+	// contains {distance} and {instruction}. With LowerFirst the first letter
+	// of the instruction is lower-cased inside it.
+	Announcement string
+	LowerFirst   bool
+
+	// Distance phrases a distance in metres, already rounded. With spoken set
+	// units are written out in full for speech synthesis.
+	Distance func(meters float64, spoken bool) string
+	// Ordinal phrases a roundabout exit number, as a word when spoken.
+	Ordinal func(n int, spoken bool) string
+
+	// Exit reads the roundabout exit number from an instruction of the API in
+	// the language of the locale, returning zero when it names none. It may
+	// be nil.
+	Exit func(instruction string) int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Bundle{}
+)
+
+// Register adds or replaces the bundle of its locale. It returns an error
+// wrapping ErrInvalidBundle if a required field is missing.
+func Register(b *Bundle) error {
+	switch {
+	case b == nil:
+		return fmt.Errorf("%w: nil", ErrInvalidBundle)
+	case b.Locale == "":
+		return fmt.Errorf("%w: no locale", ErrInvalidBundle)
+	case len(b.Actions) == 0:
+		return fmt.Errorf("%w: %s has no actions", ErrInvalidBundle, b.Locale)
+	case b.Distance == nil:
+		return fmt.Errorf("%w: %s has no distance", ErrInvalidBundle, b.Locale)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[normalizeLocale(b.Locale)] = b
+	return nil
+}
+
+// Locales returns the registered locales, sorted.
+func Locales() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	locales := make([]string, 0, len(registry))
+	for locale := range registry {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// lookup returns the bundle of the locale, falling back from a regional tag
+// such as "uk-UA" or "zh_CN" to its language.
+func lookup(locale string) (*Bundle, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	if b, ok := registry[locale]; ok {
+		return b, true
+	}
+
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		b, ok := registry[locale[:i]]
+		return b, ok
+	}
+
+	return nil, false
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// Maneuver is what an instruction is built from.
+type Maneuver struct {
+	Action   go_huawei.Action
+	RoadName string
+	// Orientation is the heading at the start of the maneuver, in degrees
+	// clockwise from north. It is only used when HasOrientation is set, so
+	// that zero can mean north.
+	Orientation    int64
+	HasOrientation bool
+	// Exit is the roundabout exit number, from 1; zero when unknown.
+	Exit int
+	// Depart marks the first maneuver of a path.
+	Depart bool
+}
+
+// FromStep returns the maneuver of a step. For roundabouts the exit number is
+// read from Step.Instruction when it names one, in the language of any
+// registered bundle with an Exit: English and Chinese among the built-in ones.
+// Other numbers, such as road numbers, are ignored. A zero Step.Orientation is taken as north only when
+// the polyline of the step starts heading north; otherwise the heading of the
+// polyline is used, if any.
+func FromStep(step go_huawei.Step) Maneuver {
+	m := Maneuver{
+		Action:         step.Action,
+		RoadName:       step.RoadName,
+		Orientation:    step.Orientation,
+		HasOrientation: step.Orientation != 0,
+	}
+
+	if !m.HasOrientation {
+		if coords := go_huawei.RemoveDuplicates(step.Polyline); len(coords) >= 2 {
+			m.Orientation = int64(math.Round(coords[0].BearingTo(coords[1]))) % 360
+			m.HasOrientation = true
+		}
+	}
+
+	if isRoundabout(step.Action) {
+		m.Exit = parseExit(step.Instruction)
+	}
+
+	return m
+}
+
+// parseExit returns the exit number named in a roundabout instruction, trying
+// the registered bundles in locale order, or zero.
+func parseExit(instruction string) int {
+	for _, locale := range Locales() {
+		if b, ok := lookup(locale); ok && b.Exit != nil {
+			if n := b.Exit(instruction); n > 0 {
+				return n
+			}
+		}
+	}
+
+	return 0
+}
+
+// exitNumber returns the exit number in digits, or zero unless it is between 1
+// and 19.
+func exitNumber(digits string) int {
+	if n, err := strconv.Atoi(digits); err == nil && n > 0 && n < 20 {
+		return n
+	}
+
+	return 0
+}
+
+func isRoundabout(action go_huawei.Action) bool {
+	return action.Category() == go_huawei.ActionCategoryRoundabout
+}
+
+// Option is the type of constructor options for New(...).
+type Option func(*Generator)
+
+// WithSpeech makes the generator produce text for speech synthesis: units and
+// ordinals are written out as words.
+func WithSpeech() Option {
+	return func(g *Generator) {
+		g.spoken = true
+	}
+}
+
+// Generator builds instructions in one locale.
+type Generator struct {
+	bundle *Bundle
+	spoken bool
+}
+
+// New returns a generator for the locale, such as "uk", "pl-PL" or "zh_CN".
+func New(locale string, options ...Option) (*Generator, error) {
+	b, ok := lookup(locale)
+	if !ok {
+		return nil, ErrUnsupportedLocale
+	}
+
+	g := &Generator{bundle: b}
+	for _, option := range options {
+		option(g)
+	}
+
+	return g, nil
+}
+
+// Instruction returns the instruction for the maneuver.
+func (g *Generator) Instruction(m Maneuver) string {
+	b := g.bundle
+
+	phrase, ok := b.Actions[m.Action]
+	if !ok {
+		phrase = b.Default
+	}
+	direction := ""
+	if m.Depart && m.HasOrientation && b.Depart.Plain != "" {
+		phrase = b.Depart
+		direction = b.Directions[int(math.Mod(float64(m.Orientation)+22.5+360, 360)/45)%8]
+	}
+
+	text := phrase.Plain
+	if m.RoadName != "" && phrase.Onto != "" {
+		text = phrase.Onto
+	}
+
+	exit := ""
+	if isRoundabout(m.Action) && m.Exit > 0 && b.Ordinal != nil {
+		exit = b.Ordinal(m.Exit, g.spoken)
+	} else if strings.Contains(text, "{exit}") {
+		text = b.EnterRoundabout.Plain
+		if m.RoadName != "" && b.EnterRoundabout.Onto != "" {
+			text = b.EnterRoundabout.Onto
+		}
+	}
+
+	return replace(text, map[string]string{
+		"{road}":      m.RoadName,
+		"{exit}":      exit,
+		"{direction}": direction,
+	})
+}
+
+// Announcement returns the instruction for the maneuver announced the given
+// distance ahead of it, in metres.
+func (g *Generator) Announcement(m Maneuver, meters float64) string {
+	instruction := g.Instruction(m)
+	if g.bundle.LowerFirst {
+		instruction = lowerFirst(instruction)
+	}
+
+	return replace(g.bundle.Announcement, map[string]string{
+		"{distance}":    g.Distance(meters),
+		"{instruction}": instruction,
+	})
+}
+
+// Distance returns the distance, in metres, rounded for display or speech:
+// to 10 m below 100 m, to 50 m below 1 km, to 0.1 km below 10 km and to whole
+// kilometres beyond.
+func (g *Generator) Distance(meters float64) string {
+	return g.bundle.Distance(roundDistance(meters), g.spoken)
+}
+
+// Step returns the instruction for a step.
+func (g *Generator) Step(step go_huawei.Step) string {
+	return g.Instruction(FromStep(step))
+}
+
+// Path returns the instructions for every step of the path, the first one
+// phrased as a departure.
+func (g *Generator) Path(p *go_huawei.Path) []string {
+	if p == nil {
+		return nil
+	}
+
+	texts := make([]string, len(p.Steps))
+	for i, step := range p.Steps {
+		m := FromStep(step)
+		m.Depart = i == 0
+		texts[i] = g.Instruction(m)
+	}
+
+	return texts
+}
+
+func roundDistance(meters float64) float64 {
+	switch {
+	case meters < 100:
+		return math.Max(10, math.Round(meters/10)*10)
+	case meters < 1000:
+		return math.Round(meters/50) * 50
+	case meters < 10000:
+		return math.Round(meters/100) * 100
+	}
+
+	return math.Round(meters/1000) * 1000
+}
+
+func replace(text string, values map[string]string) string {
+	pairs := make([]string, 0, 2*len(values))
+	for placeholder, value := range values {
+		pairs = append(pairs, placeholder, value)
+	}
+
+	return strings.Join(strings.Fields(strings.NewReplacer(pairs...).Replace(text)), " ")
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+
+	return string(unicode.ToLower(r)) + s[size:]
+}
@@ -0,0 +1,164 @@
+package instructions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+func TestParseExit(t *testing.T) {
+	tests := map[string]int{
+		"At the roundabout, take the 3rd exit onto A4": 3,
+		"Take the 1st exit":                            1,
+		"take the Second exit onto Main Street":        2,
+		"At the roundabout take exit 4":                4,
+		"Exit number 2 towards the centre":             2,
+		"exit no. 5":                                   5,
+		"Enter the roundabout and exit onto A4":        0,
+		"Take the exit onto M06":                       0,
+		"Continue on the 2nd Avenue":                   0,
+		"Take the 30th exit":                           0,
+		"":                                             0,
+		"进入环岛，从第3个出口驶出":                                3,
+		"进入环岛后从第二个出口离开，进入长安街": 2,
+		"沿环岛行驶，第 12 个出口驶出":    12,
+		"进入环岛，从第十一出口驶出":       11,
+		"进入环岛，从第二十个出口驶出":      0,
+		"在第3个路口右转进入G4":        0,
+	}
+
+	for instruction, want := range tests {
+		if got := parseExit(instruction); got != want {
+			t.Errorf("parseExit(%q) = %d, want %d", instruction, got, want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	valid := func() *Bundle {
+		return &Bundle{
+			Locale:  "x-pirate",
+			Actions: map[go_huawei.Action]Phrase{go_huawei.TurnLeft: {Plain: "Hard to port"}},
+			Distance: func(meters float64, _ bool) string {
+				return "a league"
+			},
+			Exit: func(instruction string) int {
+				if instruction == "Abandon ship at the third plank" {
+					return 3
+				}
+				return 0
+			},
+		}
+	}
+
+	for name, b := range map[string]*Bundle{
+		"nil":         nil,
+		"no locale":   func() *Bundle { b := valid(); b.Locale = ""; return b }(),
+		"no actions":  func() *Bundle { b := valid(); b.Actions = nil; return b }(),
+		"no distance": func() *Bundle { b := valid(); b.Distance = nil; return b }(),
+	} {
+		if err := Register(b); !errors.Is(err, ErrInvalidBundle) {
+			t.Errorf("%s: err = %v, want ErrInvalidBundle", name, err)
+		}
+	}
+	if _, err := New("x-pirate"); err != ErrUnsupportedLocale {
+		t.Errorf("invalid bundle was registered: err = %v", err)
+	}
+
+	if err := Register(valid()); err != nil {
+		t.Fatal(err)
+	}
+	g, err := New("X_Pirate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Instruction(Maneuver{Action: go_huawei.TurnLeft}); got != "Hard to port" {
+		t.Errorf("Instruction = %q", got)
+	}
+
+	// FromStep reads exits with the parser of every bundle.
+	step := go_huawei.Step{Action: go_huawei.RoundaboutLeft, Instruction: "Abandon ship at the third plank"}
+	if m := FromStep(step); m.Exit != 3 {
+		t.Errorf("exit from the registered bundle = %d, want 3", m.Exit)
+	}
+}
+
+func TestFromStep(t *testing.T) {
+	roundabout := go_huawei.Step{Action: go_huawei.RoundaboutRight, Instruction: "Enter the roundabout and exit onto A4", Orientation: 90}
+	if m := FromStep(roundabout); m.Exit != 0 || !m.HasOrientation || m.Orientation != 90 {
+		t.Errorf("FromStep(%q) = %+v", roundabout.Instruction, m)
+	}
+
+	// Turns never carry an exit, even when the instruction names one.
+	if m := FromStep(go_huawei.Step{Action: go_huawei.TurnLeft, Instruction: "Turn left at the 2nd exit"}); m.Exit != 0 {
+		t.Errorf("turn has exit %d", m.Exit)
+	}
+
+	north := go_huawei.Step{Polyline: []go_huawei.Coordinate{{Lat: 50, Lng: 30}, {Lat: 50, Lng: 30}, {Lat: 50.01, Lng: 30}}}
+	if m := FromStep(north); !m.HasOrientation || m.Orientation != 0 {
+		t.Errorf("northbound step = %+v, want a known orientation of 0", m)
+	}
+
+	east := go_huawei.Step{Polyline: []go_huawei.Coordinate{{Lat: 0, Lng: 30}, {Lat: 0, Lng: 30.01}}}
+	if m := FromStep(east); !m.HasOrientation || m.Orientation != 90 {
+		t.Errorf("eastbound step without orientation = %+v, want 90 from the polyline", m)
+	}
+
+	if m := FromStep(go_huawei.Step{}); m.HasOrientation {
+		t.Errorf("empty step = %+v, want an unknown orientation", m)
+	}
+}
+
+func TestInstruction(t *testing.T) {
+	g, err := New("en-GB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spoken, err := New("en", WithSpeech())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		g    *Generator
+		m    Maneuver
+		want string
+	}{
+		{g, Maneuver{Action: go_huawei.TurnLeft, RoadName: "Khreshchatyk"}, "Turn left onto Khreshchatyk"},
+		{g, Maneuver{Action: go_huawei.RoundaboutLeft, Exit: 2}, "At the roundabout, take the 2nd exit"},
+		{spoken, Maneuver{Action: go_huawei.RoundaboutLeft, Exit: 2}, "At the roundabout, take the second exit"},
+		{g, Maneuver{Action: go_huawei.RoundaboutRight, RoadName: "A4"}, "Enter the roundabout and exit onto A4"},
+		{g, Maneuver{Action: go_huawei.Action("somethingNew")}, "Continue"},
+		{g, Maneuver{Depart: true, HasOrientation: true}, "Head north"},
+		{g, Maneuver{Depart: true, HasOrientation: true, Orientation: 225, RoadName: "M06"}, "Head southwest on M06"},
+		{g, Maneuver{Depart: true, Action: go_huawei.Straight}, "Continue straight"},
+	}
+
+	for _, test := range tests {
+		if got := test.g.Instruction(test.m); got != test.want {
+			t.Errorf("Instruction(%+v) = %q, want %q", test.m, got, test.want)
+		}
+	}
+}
+
+func TestAnnouncement(t *testing.T) {
+	uk, err := New("uk_UA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := uk.Announcement(Maneuver{Action: go_huawei.TurnRight}, 230), "Через 250 м поверніть праворуч"; got != want {
+		t.Errorf("Announcement = %q, want %q", got, want)
+	}
+
+	en, _ := New("en")
+	for meters, want := range map[float64]string{4: "10 m", 87: "90 m", 1234: "1.2 km", 15600: "16 km"} {
+		if got := en.Distance(meters); got != want {
+			t.Errorf("Distance(%v) = %q, want %q", meters, got, want)
+		}
+	}
+
+	if _, err := New("xx"); err != ErrUnsupportedLocale {
+		t.Errorf("New(xx): err = %v", err)
+	}
+}
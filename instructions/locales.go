@@ -0,0 +1,342 @@
+package instructions
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+func init() {
+	for _, b := range []*Bundle{English, Ukrainian, Polish, Chinese} {
+		if err := Register(b); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// English is the built-in "en" bundle.
+var English = &Bundle{
+	Locale: "en",
+	Actions: map[go_huawei.Action]Phrase{
+		go_huawei.Straight:        {"Continue straight", "Continue straight onto {road}"},
+		go_huawei.TurnLeft:        {"Turn left", "Turn left onto {road}"},
+		go_huawei.TurnRight:       {"Turn right", "Turn right onto {road}"},
+		go_huawei.TurnSlightLeft:  {"Bear left", "Bear left onto {road}"},
+		go_huawei.TurnSlightRight: {"Bear right", "Bear right onto {road}"},
+		go_huawei.ForkLeft:        {"Keep left at the fork", "Keep left at the fork onto {road}"},
+		go_huawei.ForkRight:       {"Keep right at the fork", "Keep right at the fork onto {road}"},
+		go_huawei.RampLeft:        {"Take the ramp on the left", "Take the ramp on the left onto {road}"},
+		go_huawei.RampRight:       {"Take the ramp on the right", "Take the ramp on the right onto {road}"},
+		go_huawei.RoundaboutLeft:  {"At the roundabout, take the {exit} exit", "At the roundabout, take the {exit} exit onto {road}"},
+		go_huawei.RoundaboutRight: {"At the roundabout, take the {exit} exit", "At the roundabout, take the {exit} exit onto {road}"},
+		go_huawei.End:             {"You have arrived at your destination", ""},
+		go_huawei.TurnSharpLeft:   {"Turn sharp left", "Turn sharp left onto {road}"},
+		go_huawei.TurnSharpRight:  {"Turn sharp right", "Turn sharp right onto {road}"},
+		go_huawei.UTurnLeft:       {"Make a U-turn", "Make a U-turn onto {road}"},
+		go_huawei.UTurnRight:      {"Make a U-turn", "Make a U-turn onto {road}"},
+		go_huawei.Merge:           {"Merge", "Merge onto {road}"},
+		go_huawei.Ferry:           {"Take the ferry", "Take the ferry {road}"},
+		go_huawei.FerryTrain:      {"Take the train ferry", "Take the train ferry {road}"},
+	},
+	Default:         Phrase{"Continue", "Continue onto {road}"},
+	EnterRoundabout: Phrase{"Enter the roundabout", "Enter the roundabout and exit onto {road}"},
+	Depart:          Phrase{"Head {direction}", "Head {direction} on {road}"},
+	Directions:      [8]string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"},
+	Announcement:    "In {distance}, {instruction}",
+	LowerFirst:      true,
+	Distance: func(meters float64, spoken bool) string {
+		number, km := formatDistance(meters, ".")
+		switch {
+		case !spoken && km:
+			return number + " km"
+		case !spoken:
+			return number + " m"
+		case km && number == "1":
+			return "1 kilometer"
+		case km:
+			return number + " kilometers"
+		}
+
+		return number + " meters"
+	},
+	Ordinal: func(n int, spoken bool) string {
+		words := []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth"}
+		if spoken && n <= len(words) {
+			return words[n-1]
+		}
+
+		suffix := "th"
+		switch {
+		case n%100 >= 11 && n%100 <= 13:
+		case n%10 == 1:
+			suffix = "st"
+		case n%10 == 2:
+			suffix = "nd"
+		case n%10 == 3:
+			suffix = "rd"
+		}
+
+		return strconv.Itoa(n) + suffix
+	},
+	Exit: englishExit,
+}
+
+// Ukrainian is the built-in "uk" bundle.
+var Ukrainian = &Bundle{
+	Locale: "uk",
+	Actions: map[go_huawei.Action]Phrase{
+		go_huawei.Straight:        {"Продовжуйте рух прямо", "Продовжуйте рух прямо по {road}"},
+		go_huawei.TurnLeft:        {"Поверніть ліворуч", "Поверніть ліворуч на {road}"},
+		go_huawei.TurnRight:       {"Поверніть праворуч", "Поверніть праворуч на {road}"},
+		go_huawei.TurnSlightLeft:  {"Плавно поверніть ліворуч", "Плавно поверніть ліворуч на {road}"},
+		go_huawei.TurnSlightRight: {"Плавно поверніть праворуч", "Плавно поверніть праворуч на {road}"},
+		go_huawei.ForkLeft:        {"На розвилці тримайтеся лівіше", "На розвилці тримайтеся лівіше на {road}"},
+		go_huawei.ForkRight:       {"На розвилці тримайтеся правіше", "На розвилці тримайтеся правіше на {road}"},
+		go_huawei.RampLeft:        {"З'їдьте ліворуч", "З'їдьте ліворуч на {road}"},
+		go_huawei.RampRight:       {"З'їдьте праворуч", "З'їдьте праворуч на {road}"},
+		go_huawei.RoundaboutLeft:  {"На кільці виберіть {exit} з'їзд", "На кільці виберіть {exit} з'їзд на {road}"},
+		go_huawei.RoundaboutRight: {"На кільці виберіть {exit} з'їзд", "На кільці виберіть {exit} з'їзд на {road}"},
+		go_huawei.End:             {"Ви прибули до пункту призначення", ""},
+		go_huawei.TurnSharpLeft:   {"Різко поверніть ліворуч", "Різко поверніть ліворуч на {road}"},
+		go_huawei.TurnSharpRight:  {"Різко поверніть праворуч", "Різко поверніть праворуч на {road}"},
+		go_huawei.UTurnLeft:       {"Розверніться", "Розверніться на {road}"},
+		go_huawei.UTurnRight:      {"Розверніться", "Розверніться на {road}"},
+		go_huawei.Merge:           {"Перестройтеся в потік", "Перестройтеся в потік на {road}"},
+		go_huawei.Ferry:           {"Скористайтеся поромом", "Скористайтеся поромом {road}"},
+		go_huawei.FerryTrain:      {"Скористайтеся залізничним поромом", "Скористайтеся залізничним поромом {road}"},
+	},
+	Default:         Phrase{"Продовжуйте рух", "Продовжуйте рух по {road}"},
+	EnterRoundabout: Phrase{"В'їдьте на кільце", "В'їдьте на кільце та з'їдьте на {road}"},
+	Depart:          Phrase{"Рухайтеся на {direction}", "Рухайтеся на {direction} по {road}"},
+	Directions:      [8]string{"північ", "північний схід", "схід", "південний схід", "південь", "південний захід", "захід", "північний захід"},
+	Announcement:    "Через {distance} {instruction}",
+	LowerFirst:      true,
+	Distance: func(meters float64, spoken bool) string {
+		number, km := formatDistance(meters, ",")
+		switch {
+		case !spoken && km:
+			return number + " км"
+		case !spoken:
+			return number + " м"
+		case km:
+			return number + " " + ukrainianPlural(number, "кілометр", "кілометри", "кілометрів", "кілометра")
+		}
+
+		return number + " " + ukrainianPlural(number, "метр", "метри", "метрів", "метра")
+	},
+	Ordinal: func(n int, spoken bool) string {
+		words := []string{"перший", "другий", "третій", "четвертий", "п'ятий", "шостий", "сьомий", "восьмий", "дев'ятий", "десятий"}
+		if spoken && n <= len(words) {
+			return words[n-1]
+		}
+
+		return strconv.Itoa(n) + "-й"
+	},
+}
+
+// Polish is the built-in "pl" bundle.
+var Polish = &Bundle{
+	Locale: "pl",
+	Actions: map[go_huawei.Action]Phrase{
+		go_huawei.Straight:        {"Jedź prosto", "Jedź prosto w {road}"},
+		go_huawei.TurnLeft:        {"Skręć w lewo", "Skręć w lewo w {road}"},
+		go_huawei.TurnRight:       {"Skręć w prawo", "Skręć w prawo w {road}"},
+		go_huawei.TurnSlightLeft:  {"Skręć lekko w lewo", "Skręć lekko w lewo w {road}"},
+		go_huawei.TurnSlightRight: {"Skręć lekko w prawo", "Skręć lekko w prawo w {road}"},
+		go_huawei.ForkLeft:        {"Na rozwidleniu trzymaj się lewej strony", "Na rozwidleniu trzymaj się lewej strony i jedź w {road}"},
+		go_huawei.ForkRight:       {"Na rozwidleniu trzymaj się prawej strony", "Na rozwidleniu trzymaj się prawej strony i jedź w {road}"},
+		go_huawei.RampLeft:        {"Zjedź w lewo", "Zjedź w lewo w {road}"},
+		go_huawei.RampRight:       {"Zjedź w prawo", "Zjedź w prawo w {road}"},
+		go_huawei.RoundaboutLeft:  {"Na rondzie zjedź {exit} zjazdem", "Na rondzie zjedź {exit} zjazdem w {road}"},
+		go_huawei.RoundaboutRight: {"Na rondzie zjedź {exit} zjazdem", "Na rondzie zjedź {exit} zjazdem w {road}"},
+		go_huawei.End:             {"Jesteś u celu", ""},
+		go_huawei.TurnSharpLeft:   {"Skręć ostro w lewo", "Skręć ostro w lewo w {road}"},
+		go_huawei.TurnSharpRight:  {"Skręć ostro w prawo", "Skręć ostro w prawo w {road}"},
+		go_huawei.UTurnLeft:       {"Zawróć", "Zawróć w {road}"},
+		go_huawei.UTurnRight:      {"Zawróć", "Zawróć w {road}"},
+		go_huawei.Merge:           {"Włącz się do ruchu", "Włącz się do ruchu na {road}"},
+		go_huawei.Ferry:           {"Wjedź na prom", "Wjedź na prom {road}"},
+		go_huawei.FerryTrain:      {"Wjedź na prom kolejowy", "Wjedź na prom kolejowy {road}"},
+	},
+	Default:         Phrase{"Jedź dalej", "Jedź dalej w {road}"},
+	EnterRoundabout: Phrase{"Wjedź na rondo", "Wjedź na rondo i zjedź w {road}"},
+	Depart:          Phrase{"Kieruj się na {direction}", "Kieruj się na {direction} drogą {road}"},
+	Directions:      [8]string{"północ", "północny wschód", "wschód", "południowy wschód", "południe", "południowy zachód", "zachód", "północny zachód"},
+	Announcement:    "Za {distance} {instruction}",
+	LowerFirst:      true,
+	Distance: func(meters float64, spoken bool) string {
+		number, km := formatDistance(meters, ",")
+		switch {
+		case !spoken && km:
+			return number + " km"
+		case !spoken:
+			return number + " m"
+		case km:
+			return number + " " + polishPlural(number, "kilometr", "kilometry", "kilometrów", "kilometra")
+		}
+
+		return number + " " + polishPlural(number, "metr", "metry", "metrów", "metra")
+	},
+	Ordinal: func(n int, spoken bool) string {
+		words := []string{"pierwszym", "drugim", "trzecim", "czwartym", "piątym", "szóstym", "siódmym", "ósmym", "dziewiątym", "dziesiątym"}
+		if spoken && n <= len(words) {
+			return words[n-1]
+		}
+
+		return strconv.Itoa(n) + "."
+	},
+}
+
+// Chinese is the built-in "zh" bundle.
+var Chinese = &Bundle{
+	Locale: "zh",
+	Actions: map[go_huawei.Action]Phrase{
+		go_huawei.Straight:        {"直行", "直行进入{road}"},
+		go_huawei.TurnLeft:        {"左转", "左转进入{road}"},
+		go_huawei.TurnRight:       {"右转", "右转进入{road}"},
+		go_huawei.TurnSlightLeft:  {"向左前方行驶", "向左前方行驶进入{road}"},
+		go_huawei.TurnSlightRight: {"向右前方行驶", "向右前方行驶进入{road}"},
+		go_huawei.ForkLeft:        {"在岔路口靠左", "在岔路口靠左进入{road}"},
+		go_huawei.ForkRight:       {"在岔路口靠右", "在岔路口靠右进入{road}"},
+		go_huawei.RampLeft:        {"靠左进入匝道", "靠左进入匝道驶入{road}"},
+		go_huawei.RampRight:       {"靠右进入匝道", "靠右进入匝道驶入{road}"},
+		go_huawei.RoundaboutLeft:  {"进入环岛，从{exit}出口驶出", "进入环岛，从{exit}出口驶出进入{road}"},
+		go_huawei.RoundaboutRight: {"进入环岛，从{exit}出口驶出", "进入环岛，从{exit}出口驶出进入{road}"},
+		go_huawei.End:             {"到达目的地", ""},
+		go_huawei.TurnSharpLeft:   {"向左后方行驶", "向左后方行驶进入{road}"},
+		go_huawei.TurnSharpRight:  {"向右后方行驶", "向右后方行驶进入{road}"},
+		go_huawei.UTurnLeft:       {"掉头", "掉头进入{road}"},
+		go_huawei.UTurnRight:      {"掉头", "掉头进入{road}"},
+		go_huawei.Merge:           {"并入主路", "并入{road}"},
+		go_huawei.Ferry:           {"乘坐轮渡", "乘坐{road}轮渡"},
+		go_huawei.FerryTrain:      {"乘坐火车轮渡", "乘坐{road}火车轮渡"},
+	},
+	Default:         Phrase{"继续行驶", "沿{road}继续行驶"},
+	EnterRoundabout: Phrase{"进入环岛", "进入环岛后驶入{road}"},
+	Depart:          Phrase{"向{direction}出发", "沿{road}向{direction}出发"},
+	Directions:      [8]string{"北", "东北", "东", "东南", "南", "西南", "西", "西北"},
+	Announcement:    "{distance}后{instruction}",
+	Distance: func(meters float64, _ bool) string {
+		number, km := formatDistance(meters, ".")
+		if km {
+			return number + "公里"
+		}
+
+		return number + "米"
+	},
+	Ordinal: func(n int, spoken bool) string {
+		numerals := []string{"一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+		if spoken && n <= len(numerals) {
+			return "第" + numerals[n-1] + "个"
+		}
+
+		return "第" + strconv.Itoa(n) + "个"
+	},
+	Exit: chineseExit,
+}
+
+// englishExitPattern matches the exit of a roundabout instruction: "3rd exit",
+// "third exit", "exit 3" or "exit number 3".
+var englishExitPattern = regexp.MustCompile(`(?i)\b(?:(\d{1,2})(?:st|nd|rd|th)|(first|second|third|fourth|fifth|sixth|seventh|eighth|ninth|tenth))\s+exit\b|\bexit\s+(?:number\s+|no\.\s*)?(\d{1,2})\b`)
+
+var englishExitWords = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+	"sixth": 6, "seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10,
+}
+
+func englishExit(instruction string) int {
+	match := englishExitPattern.FindStringSubmatch(instruction)
+	switch {
+	case match == nil:
+		return 0
+	case match[2] != "":
+		return englishExitWords[strings.ToLower(match[2])]
+	case match[1] != "":
+		return exitNumber(match[1])
+	}
+
+	return exitNumber(match[3])
+}
+
+// chineseExitPattern matches the exit of a roundabout instruction, in digits
+// or numerals: "第3个出口" or "第三出口".
+var chineseExitPattern = regexp.MustCompile(`第\s*(\d{1,2}|[一二三四五六七八九十]{1,2})\s*[个個]?\s*出口`)
+
+const chineseNumerals = "一二三四五六七八九十"
+
+func chineseExit(instruction string) int {
+	match := chineseExitPattern.FindStringSubmatch(instruction)
+	if match == nil {
+		return 0
+	}
+
+	digits := []rune(match[1])
+	value := func(r rune) int {
+		return strings.IndexRune(chineseNumerals, r)/len("一") + 1
+	}
+	switch {
+	case digits[0] < utf8.RuneSelf:
+		return exitNumber(match[1])
+	case len(digits) == 1:
+		return value(digits[0])
+	case digits[0] == '十' && digits[1] != '十':
+		// 十一 to 十九.
+		return 10 + value(digits[1])
+	}
+
+	return 0
+}
+
+// formatDistance returns a rounded distance as a number of metres below 1 km
+// and of kilometres, with at most one decimal, from 1 km.
+func formatDistance(meters float64, decimalSeparator string) (number string, km bool) {
+	if meters < 1000 {
+		return strconv.Itoa(int(meters)), false
+	}
+
+	number = strconv.FormatFloat(meters/1000, 'f', 1, 64)
+	number = strings.TrimSuffix(number, ".0")
+	return strings.Replace(number, ".", decimalSeparator, 1), true
+}
+
+// ukrainianPlural picks the noun form for a number: one for numbers ending in 1
+// except 11, few for numbers ending in 2-4 except 12-14, many for other
+// integers and fraction for decimals.
+func ukrainianPlural(number, one, few, many, fraction string) string {
+	if strings.ContainsAny(number, ".,") {
+		return fraction
+	}
+
+	n, _ := strconv.Atoi(number)
+	if n%10 == 1 && n%100 != 11 {
+		return one
+	}
+
+	return slavicFewOrMany(n, few, many)
+}
+
+// polishPlural picks the noun form for a number: one for 1 only, then as
+// ukrainianPlural.
+func polishPlural(number, one, few, many, fraction string) string {
+	if strings.ContainsAny(number, ".,") {
+		return fraction
+	}
+
+	n, _ := strconv.Atoi(number)
+	if n == 1 {
+		return one
+	}
+
+	return slavicFewOrMany(n, few, many)
+}
+
+func slavicFewOrMany(n int, few, many string) string {
+	if n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14) {
+		return few
+	}
+
+	return many
+}
@@ -0,0 +1,52 @@
+// Package bbox converts bounds to and from the polygon rings written by the wkt
+// and wkb packages.
+package bbox
+
+import (
+	"math"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+// Ring returns b as a closed counter-clockwise ring starting at the south-west
+// corner. Bounds crossing the antimeridian get an east edge beyond 180 so that
+// the polygon covers the right side of the globe.
+func Ring(b go_huawei.CoordinateBounds) []go_huawei.Coordinate {
+	west, east := b.Southwest.Lng, b.Northeast.Lng
+	if b.CrossesAntimeridian() {
+		east += 360
+	}
+	south, north := b.Southwest.Lat, b.Northeast.Lat
+
+	return []go_huawei.Coordinate{
+		{Lng: west, Lat: south},
+		{Lng: east, Lat: south},
+		{Lng: east, Lat: north},
+		{Lng: west, Lat: north},
+		{Lng: west, Lat: south},
+	}
+}
+
+// FromRing returns the bounds of a ring. Longitudes beyond 180, as written by
+// Ring, are wrapped back. It returns empty bounds for an empty ring.
+func FromRing(ring []go_huawei.Coordinate) go_huawei.CoordinateBounds {
+	if len(ring) == 0 {
+		return go_huawei.CoordinateBounds{}
+	}
+
+	south, north := ring[0].Lat, ring[0].Lat
+	west, east := ring[0].Lng, ring[0].Lng
+	for _, c := range ring[1:] {
+		south, north = math.Min(south, c.Lat), math.Max(north, c.Lat)
+		west, east = math.Min(west, c.Lng), math.Max(east, c.Lng)
+	}
+
+	if east > 180 {
+		east -= 360
+	}
+
+	return go_huawei.CoordinateBounds{
+		Southwest: go_huawei.Coordinate{Lat: south, Lng: west},
+		Northeast: go_huawei.Coordinate{Lat: north, Lng: east},
+	}
+}
@@ -0,0 +1,43 @@
+// Package mercator holds the Web Mercator math shared by the root package,
+// projection and render.
+package mercator
+
+import "math"
+
+const (
+	// TileSize is the width and height of the world at zoom 0, in pixels.
+	TileSize = 256
+	// MaxLatitude is the latitude where Web Mercator is cut off, making the
+	// world square.
+	MaxLatitude = 85.05112877980659
+)
+
+// WorldSize returns the width and height of the world at the zoom, in pixels.
+func WorldSize(zoom float64) float64 {
+	return TileSize * math.Exp2(zoom)
+}
+
+// Y returns the Web Mercator ordinate of a latitude, in radians of the equator:
+// 0 at the equator and ±π at ±MaxLatitude.
+func Y(lat float64) float64 {
+	lat = math.Max(-MaxLatitude, math.Min(MaxLatitude, lat))
+	return math.Log(math.Tan(math.Pi/4 + lat*math.Pi/360))
+}
+
+// FitZoom returns the fractional zoom, at most maxZoom, at which the latitudes
+// south to north and lngSpan degrees of longitude fit into width by height
+// pixels.
+func FitZoom(south, north, lngSpan, width, height, maxZoom float64) float64 {
+	latFraction := (Y(north) - Y(south)) / (2 * math.Pi)
+	lngFraction := lngSpan / 360
+
+	zoom := maxZoom
+	if latFraction > 0 && height > 0 {
+		zoom = math.Min(zoom, math.Log2(height/TileSize/latFraction))
+	}
+	if lngFraction > 0 && width > 0 {
+		zoom = math.Min(zoom, math.Log2(width/TileSize/lngFraction))
+	}
+
+	return zoom
+}
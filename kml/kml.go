@@ -0,0 +1,371 @@
+// Package kml writes routes as KML documents and zipped KMZ archives for review
+// in Google Earth and desktop GIS.
+package kml
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+const (
+	namespace        = "http://www.opengis.net/kml/2.2"
+	defaultLineWidth = 4
+
+	originStyle      = "origin"
+	destinationStyle = "destination"
+	waypointStyle    = "waypoint"
+	stepStyle        = "step"
+)
+
+// DefaultColors are the line colours of successive alternatives.
+var DefaultColors = []color.Color{
+	color.NRGBA{R: 0x1a, G: 0x73, B: 0xe8, A: 0xff},
+	color.NRGBA{R: 0x75, G: 0x75, B: 0x75, A: 0xcc},
+	color.NRGBA{R: 0xe3, G: 0x74, B: 0x00, A: 0xcc},
+	color.NRGBA{R: 0x9c, G: 0x27, B: 0xb0, A: 0xcc},
+}
+
+// Option is the type of options for Write(...) and WriteKMZ(...).
+type Option func(*writer)
+
+// WithName sets the name of the document.
+func WithName(name string) Option {
+	return func(w *writer) {
+		w.name = name
+	}
+}
+
+// WithColors sets the line colours of successive alternatives, cycling when
+// there are more alternatives than colours. Default is DefaultColors.
+func WithColors(colors ...color.Color) Option {
+	return func(w *writer) {
+		w.colors = colors
+	}
+}
+
+// WithLineWidth sets the width of route lines, in pixels. Default is 4.
+func WithLineWidth(width float64) Option {
+	return func(w *writer) {
+		w.lineWidth = width
+	}
+}
+
+// WithWaypoints adds placemarks for the waypoints the routes were requested
+// with; responses do not carry them.
+func WithWaypoints(waypoints ...go_huawei.Coordinate) Option {
+	return func(w *writer) {
+		w.waypoints = waypoints
+	}
+}
+
+// WithoutSteps omits the per-step placemarks.
+func WithoutSteps() Option {
+	return func(w *writer) {
+		w.noSteps = true
+	}
+}
+
+type writer struct {
+	name      string
+	colors    []color.Color
+	lineWidth float64
+	waypoints []go_huawei.Coordinate
+	noSteps   bool
+}
+
+type document struct {
+	XMLName  xml.Name `xml:"kml"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Document body     `xml:"Document"`
+}
+
+type body struct {
+	Name       string      `xml:"name,omitempty"`
+	Styles     []style     `xml:"Style"`
+	Placemarks []placemark `xml:"Placemark"`
+	Folders    []folder    `xml:"Folder"`
+}
+
+type folder struct {
+	Name       string      `xml:"name"`
+	Visibility *int        `xml:"visibility,omitempty"`
+	Open       int         `xml:"open,omitempty"`
+	Placemarks []placemark `xml:"Placemark"`
+	Folders    []folder    `xml:"Folder"`
+}
+
+type style struct {
+	ID        string     `xml:"id,attr"`
+	LineStyle *lineStyle `xml:"LineStyle,omitempty"`
+	IconStyle *iconStyle `xml:"IconStyle,omitempty"`
+}
+
+type lineStyle struct {
+	Color string  `xml:"color"`
+	Width float64 `xml:"width"`
+}
+
+type iconStyle struct {
+	Color string  `xml:"color,omitempty"`
+	Scale float64 `xml:"scale,omitempty"`
+	Icon  icon    `xml:"Icon"`
+}
+
+type icon struct {
+	Href string `xml:"href"`
+}
+
+type placemark struct {
+	Name        string      `xml:"name,omitempty"`
+	Description string      `xml:"description,omitempty"`
+	StyleURL    string      `xml:"styleUrl,omitempty"`
+	Point       *point      `xml:"Point,omitempty"`
+	LineString  *lineString `xml:"LineString,omitempty"`
+}
+
+type point struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type lineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// Write writes the routes as a KML 2.2 document. Every path is a styled
+// LineString in its own folder, with a placemark per step describing the
+// maneuver; the origin and destination of every path and the waypoints get
+// placemarks of their own, one per distinct location.
+func Write(w io.Writer, routes []go_huawei.Route, options ...Option) error {
+	cfg := &writer{colors: DefaultColors, lineWidth: defaultLineWidth}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	doc := cfg.document(routes)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("kml: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteKMZ writes the routes as a KMZ archive holding the Write output as
+// doc.kml.
+func WriteKMZ(w io.Writer, routes []go_huawei.Route, options ...Option) error {
+	archive := zip.NewWriter(w)
+
+	entry, err := archive.Create("doc.kml")
+	if err != nil {
+		return fmt.Errorf("kml: %w", err)
+	}
+
+	if err := Write(entry, routes, options...); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("kml: %w", err)
+	}
+
+	return nil
+}
+
+func (cfg *writer) document(routes []go_huawei.Route) document {
+	doc := document{Xmlns: namespace, Document: body{Name: cfg.name}}
+
+	doc.Document.Styles = []style{
+		pinStyle(originStyle, "http://maps.google.com/mapfiles/kml/paddle/grn-circle.png", nil),
+		pinStyle(destinationStyle, "http://maps.google.com/mapfiles/kml/paddle/red-circle.png", nil),
+		pinStyle(waypointStyle, "http://maps.google.com/mapfiles/kml/paddle/ylw-circle.png", nil),
+		pinStyle(stepStyle, "http://maps.google.com/mapfiles/kml/shapes/placemark_circle.png", &iconStyle{Scale: 0.6}),
+	}
+
+	alternative := 0
+	var origins, destinations []placemark
+	pinned := make(map[string]bool)
+	for i := range routes {
+		routeFolder := folder{Name: "Route " + strconv.Itoa(i+1), Open: 1}
+
+		for j := range routes[i].Paths {
+			path := &routes[i].Paths[j]
+
+			// Alternatives usually share their endpoints; pin each location once.
+			if origin := pin("Origin", path.StartAddress, originStyle, path.StartLocation); !pinned[originStyle+origin.Point.Coordinates] {
+				pinned[originStyle+origin.Point.Coordinates] = true
+				origins = append(origins, origin)
+			}
+			if destination := pin("Destination", path.EndAddress, destinationStyle, path.EndLocation); !pinned[destinationStyle+destination.Point.Coordinates] {
+				pinned[destinationStyle+destination.Point.Coordinates] = true
+				destinations = append(destinations, destination)
+			}
+
+			styleID := "alternative-" + strconv.Itoa(alternative)
+			doc.Document.Styles = append(doc.Document.Styles, style{
+				ID:        styleID,
+				LineStyle: &lineStyle{Color: cfg.color(alternative), Width: cfg.lineWidth},
+			})
+
+			routeFolder.Folders = append(routeFolder.Folders, cfg.pathFolder(path, alternative, styleID))
+			alternative++
+		}
+
+		doc.Document.Folders = append(doc.Document.Folders, routeFolder)
+	}
+
+	if alternative > 0 {
+		doc.Document.Placemarks = append(doc.Document.Placemarks, origins...)
+		for i, c := range cfg.waypoints {
+			doc.Document.Placemarks = append(doc.Document.Placemarks, pin("Waypoint "+strconv.Itoa(i+1), "", waypointStyle, c))
+		}
+		doc.Document.Placemarks = append(doc.Document.Placemarks, destinations...)
+	}
+
+	return doc
+}
+
+func (cfg *writer) pathFolder(path *go_huawei.Path, alternative int, styleID string) folder {
+	name := "Alternative " + strconv.Itoa(alternative)
+	if alternative == 0 {
+		name = "Primary"
+	}
+
+	f := folder{
+		Name: name,
+		Placemarks: []placemark{{
+			Name:        name,
+			Description: pathDescription(path),
+			StyleURL:    "#" + styleID,
+			LineString:  &lineString{Tessellate: 1, Coordinates: formatCoordinates(go_huawei.RemoveDuplicates(path.Overview()))},
+		}},
+	}
+
+	if cfg.noSteps || len(path.Steps) == 0 {
+		return f
+	}
+
+	steps := folder{Name: "Steps"}
+	if alternative > 0 {
+		hidden := 0
+		steps.Visibility = &hidden
+	}
+	for i, step := range path.Steps {
+		steps.Placemarks = append(steps.Placemarks, placemark{
+			Name:        strconv.Itoa(i+1) + ". " + stepName(step),
+			Description: stepDescription(step),
+			StyleURL:    "#" + stepStyle,
+			Point:       &point{Coordinates: formatCoordinates([]go_huawei.Coordinate{step.StartLocation})},
+		})
+	}
+	f.Folders = []folder{steps}
+
+	return f
+}
+
+func (cfg *writer) color(i int) string {
+	if len(cfg.colors) == 0 {
+		return colorString(DefaultColors[i%len(DefaultColors)])
+	}
+
+	return colorString(cfg.colors[i%len(cfg.colors)])
+}
+
+func pinStyle(id, href string, base *iconStyle) style {
+	s := iconStyle{}
+	if base != nil {
+		s = *base
+	}
+	s.Icon = icon{Href: href}
+
+	return style{ID: id, IconStyle: &s}
+}
+
+func pin(name, description, styleID string, c go_huawei.Coordinate) placemark {
+	return placemark{
+		Name:        name,
+		Description: description,
+		StyleURL:    "#" + styleID,
+		Point:       &point{Coordinates: formatCoordinates([]go_huawei.Coordinate{c})},
+	}
+}
+
+func pathDescription(path *go_huawei.Path) string {
+	traffic := ""
+	if path.DurationInTrafficText != "" && path.DurationInTrafficText != path.DurationText {
+		traffic = path.DurationInTrafficText + " in traffic"
+	}
+
+	return join(path.DistanceText, path.DurationText, traffic)
+}
+
+func stepName(step go_huawei.Step) string {
+	switch {
+	case step.Instruction != "":
+		return step.Instruction
+	case step.RoadName != "":
+		return step.RoadName
+	}
+
+	return string(step.Action)
+}
+
+func stepDescription(step go_huawei.Step) string {
+	lines := []string{}
+	if step.Instruction != "" {
+		lines = append(lines, step.Instruction)
+	}
+	if step.RoadName != "" {
+		lines = append(lines, "Road: "+step.RoadName)
+	}
+	if step.Action != "" {
+		lines = append(lines, "Action: "+string(step.Action))
+	}
+	if s := join(step.DistanceText, step.DurationText); s != "" {
+		lines = append(lines, s)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func join(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+
+	return strings.Join(nonEmpty, ", ")
+}
+
+// formatCoordinates returns the KML coordinates of a point or line:
+// "lng,lat" tuples separated by spaces.
+func formatCoordinates(coords []go_huawei.Coordinate) string {
+	tuples := make([]string, len(coords))
+	for i, c := range coords {
+		tuples[i] = strconv.FormatFloat(c.Lng, 'f', -1, 64) + "," + strconv.FormatFloat(c.Lat, 'f', -1, 64)
+	}
+
+	return strings.Join(tuples, " ")
+}
+
+// colorString returns c in the aabbggrr hex notation of KML.
+func colorString(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("%02x%02x%02x%02x", n.A, n.B, n.G, n.R)
+}
@@ -0,0 +1,155 @@
+package kml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+func testPath(from, to go_huawei.Coordinate, address string) go_huawei.Path {
+	return go_huawei.Path{
+		StartLocation: from,
+		EndLocation:   to,
+		EndAddress:    address,
+		DistanceText:  "1.2km",
+		DurationText:  "3min",
+		Steps: []go_huawei.Step{
+			{StartLocation: from, EndLocation: to, Polyline: []go_huawei.Coordinate{from, to}, Instruction: "Head east", Action: go_huawei.Straight},
+		},
+	}
+}
+
+var (
+	kyiv    = go_huawei.Coordinate{Lat: 50.45, Lng: 30.52}
+	brovary = go_huawei.Coordinate{Lat: 50.51, Lng: 30.79}
+	irpin   = go_huawei.Coordinate{Lat: 50.52, Lng: 30.25}
+)
+
+func TestWrite(t *testing.T) {
+	routes := []go_huawei.Route{
+		{Paths: []go_huawei.Path{testPath(kyiv, brovary, "Brovary"), testPath(kyiv, brovary, "Brovary")}},
+		{Paths: []go_huawei.Path{testPath(kyiv, irpin, "Irpin")}},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, routes, WithName("Trips"), WithWaypoints(irpin)); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc document
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("%v in\n%s", err, buf.String())
+	}
+
+	var pins []string
+	for _, p := range doc.Document.Placemarks {
+		pins = append(pins, p.Name+" "+p.Point.Coordinates)
+	}
+	want := []string{"Origin 30.52,50.45", "Waypoint 1 30.25,50.52", "Destination 30.79,50.51", "Destination 30.25,50.52"}
+	if strings.Join(pins, "; ") != strings.Join(want, "; ") {
+		t.Errorf("pins = %q, want %q", pins, want)
+	}
+
+	if len(doc.Document.Folders) != 2 || len(doc.Document.Folders[0].Folders) != 2 {
+		t.Fatalf("folders = %+v", doc.Document.Folders)
+	}
+	alternative := doc.Document.Folders[0].Folders[1]
+	if alternative.Name != "Alternative 1" || alternative.Folders[0].Visibility == nil || *alternative.Folders[0].Visibility != 0 {
+		t.Errorf("alternative folder = %+v, want hidden steps", alternative)
+	}
+	if line := alternative.Placemarks[0]; line.StyleURL != "#alternative-1" || line.LineString.Coordinates != "30.52,50.45 30.79,50.51" {
+		t.Errorf("alternative line = %+v", line)
+	}
+
+	// KML requires name, visibility, open in this order.
+	folder := regexp.MustCompile(`(?s)<Folder>\s*<name>Route 1</name>\s*<open>1</open>`)
+	if !folder.Match(buf.Bytes()) {
+		t.Errorf("route folder is not name, open:\n%s", buf.String())
+	}
+	steps := regexp.MustCompile(`(?s)<name>Steps</name>\s*<visibility>0</visibility>`)
+	if !steps.Match(buf.Bytes()) {
+		t.Errorf("hidden steps folder is not name, visibility:\n%s", buf.String())
+	}
+}
+
+func TestWriteWithoutSteps(t *testing.T) {
+	var buf bytes.Buffer
+	routes := []go_huawei.Route{{Paths: []go_huawei.Path{testPath(kyiv, brovary, "")}}}
+	if err := Write(&buf, routes, WithoutSteps(), WithColors(color.RGBA{R: 0xff, A: 0xff}), WithLineWidth(2.5)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "Steps") {
+		t.Error("steps were written")
+	}
+	if !strings.Contains(out, "<color>ff0000ff</color>") || !strings.Contains(out, "<width>2.5</width>") {
+		t.Errorf("line style not applied:\n%s", out)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<Placemark>") {
+		t.Errorf("placemarks without routes:\n%s", buf.String())
+	}
+}
+
+func TestWriteKMZ(t *testing.T) {
+	routes := []go_huawei.Route{{Paths: []go_huawei.Path{testPath(kyiv, irpin, "Irpin")}}}
+
+	var kml, kmz bytes.Buffer
+	if err := Write(&kml, routes); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteKMZ(&kmz, routes); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(kmz.Bytes()), int64(kmz.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.File) != 1 || archive.File[0].Name != "doc.kml" {
+		t.Fatalf("archive holds %v", archive.File)
+	}
+
+	entry, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer entry.Close()
+
+	data, err := ioutil.ReadAll(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, kml.Bytes()) {
+		t.Error("doc.kml differs from Write output")
+	}
+}
+
+func TestColorString(t *testing.T) {
+	tests := map[color.Color]string{
+		color.NRGBA{R: 0x1a, G: 0x73, B: 0xe8, A: 0xff}: "ffe8731a",
+		color.NRGBA{R: 0xff, A: 0x80}:                   "800000ff",
+		color.RGBA{R: 0x40, G: 0x40, A: 0x80}:           "80007f7f",
+		color.Gray{Y: 0x10}:                             "ff101010",
+	}
+
+	for c, want := range tests {
+		if got := colorString(c); got != want {
+			t.Errorf("colorString(%v) = %s, want %s", c, got, want)
+		}
+	}
+}
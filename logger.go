@@ -0,0 +1,178 @@
+package go_huawei
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Logger is the interface used by the Client to report request activity. Its
+// method set matches *slog.Logger, so a slog logger can be passed directly;
+// args are alternating key/value pairs.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// LogLevel is the severity at which the Client reports request activity.
+type LogLevel int
+
+// Log levels, from the most to the least verbose.
+const (
+	LogLevelDebug = LogLevel(0)
+	LogLevelInfo  = LogLevel(1)
+	LogLevelWarn  = LogLevel(2)
+	LogLevelError = LogLevel(3)
+)
+
+const redactedValue = "REDACTED"
+
+var (
+	defaultLogLevel      = LogLevelDebug
+	defaultErrorLogLevel = LogLevelError
+)
+
+// WithLogger configures a Maps API client to log the start and end of every
+// request. Logging is disabled unless a logger is configured.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithLogLevel configures the level at which request start and end are logged.
+// Default is LogLevelDebug.
+func WithLogLevel(level LogLevel) ClientOption {
+	return func(c *Client) error {
+		c.logLevel = level
+		return nil
+	}
+}
+
+// WithErrorLogLevel configures the level at which failed requests (transport
+// errors, HTTP errors and non-OK ReturnCodes) are logged. Default is
+// LogLevelError.
+func WithErrorLogLevel(level LogLevel) ClientOption {
+	return func(c *Client) error {
+		c.errorLogLevel = level
+		return nil
+	}
+}
+
+// WithLogBodies enables debug dumps of request and response bodies, truncated to
+// limit bytes. A value of zero disables the dumps.
+func WithLogBodies(limit int) ClientOption {
+	return func(c *Client) error {
+		c.logBodyLimit = limit
+		return nil
+	}
+}
+
+func (c *Client) log(level LogLevel, msg string, args ...interface{}) {
+	if c.logger == nil {
+		return
+	}
+
+	switch {
+	case level >= LogLevelError:
+		c.logger.Error(msg, args...)
+	case level >= LogLevelWarn:
+		c.logger.Warn(msg, args...)
+	case level >= LogLevelInfo:
+		c.logger.Info(msg, args...)
+	default:
+		c.logger.Debug(msg, args...)
+	}
+}
+
+func (c *Client) logRequestStart(req *http.Request) {
+	if c.logger == nil {
+		return
+	}
+
+	c.log(c.logLevel, "map-kit: request started", "method", req.Method, "url", redactURL(req.URL))
+
+	if c.logBodyLimit > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return
+		}
+		defer body.Close()
+
+		raw, err := ioutil.ReadAll(body)
+		if err != nil {
+			return
+		}
+
+		c.log(LogLevelDebug, "map-kit: request body", "url", redactURL(req.URL), "body", truncateBody(raw, c.logBodyLimit))
+	}
+}
+
+func (c *Client) logRequestEnd(req *http.Request, start time.Time, statusCode int, body []byte, err error) {
+	if c.logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"method", req.Method,
+		"url", redactURL(req.URL),
+		"latency", time.Since(start),
+	}
+
+	failed := err != nil
+	if err != nil {
+		args = append(args, "error", err.Error())
+	}
+
+	if statusCode != 0 {
+		args = append(args, "status", statusCode)
+		failed = failed || statusCode >= http.StatusBadRequest
+	}
+
+	if len(body) > 0 {
+		var common CommonResponse
+		if json.Unmarshal(body, &common) == nil && common.ReturnCode != "" {
+			args = append(args, "returnCode", string(common.ReturnCode), "returnDesc", string(common.ReturnDesc))
+			failed = failed || common.StatusError() != nil
+		}
+	}
+
+	level := c.logLevel
+	if failed {
+		level = c.errorLogLevel
+	}
+	c.log(level, "map-kit: request finished", args...)
+
+	if c.logBodyLimit > 0 && len(body) > 0 {
+		c.log(LogLevelDebug, "map-kit: response body", "url", redactURL(req.URL), "body", truncateBody(body, c.logBodyLimit))
+	}
+}
+
+// redactURL returns the URL as a string with the API key replaced.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	redacted := *u
+	q := redacted.Query()
+	if q.Get("key") != "" {
+		q.Set("key", redactedValue)
+		redacted.RawQuery = q.Encode()
+	}
+
+	return redacted.String()
+}
+
+func truncateBody(body []byte, limit int) string {
+	if len(body) <= limit {
+		return string(body)
+	}
+
+	return string(body[:limit]) + "...(truncated)"
+}
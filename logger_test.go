@@ -0,0 +1,179 @@
+package go_huawei
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  map[string]interface{}
+}
+
+type testLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *testLogger) add(level, msg string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := logEntry{level: level, msg: msg, args: map[string]interface{}{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[fmt.Sprint(args[i])] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.add("debug", msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.add("info", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.add("warn", msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.add("error", msg, args) }
+
+func (l *testLogger) find(msg string) (logEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, entry := range l.entries {
+		if entry.msg == msg {
+			return entry, true
+		}
+	}
+
+	return logEntry{}, false
+}
+
+func newLoggedClient(t *testing.T, body string, options ...ClientOption) (*Client, *testLogger) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	logger := &testLogger{}
+	options = append([]ClientOption{WithAPIKey("secret"), WithBaseURL(server.URL), WithLogger(logger)}, options...)
+	client, err := NewClient(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, logger
+}
+
+func directionsRequest() *DirectionsRequest {
+	return &DirectionsRequest{
+		Origin:       &Coordinate{Lat: 50.45, Lng: 30.52},
+		Destination:  &Coordinate{Lat: 50.40, Lng: 30.60},
+		RouteService: RouteServiceDriving,
+	}
+}
+
+func TestLoggerRedactsKey(t *testing.T) {
+	client, logger := newLoggedClient(t, `{"returnCode":"0","returnDesc":"OK","routes":[]}`)
+
+	if _, err := client.Directions(context.Background(), directionsRequest()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{"map-kit: request started", "map-kit: request finished"} {
+		entry, ok := logger.find(msg)
+		if !ok {
+			t.Fatalf("%q not logged", msg)
+		}
+		if entry.level != "debug" {
+			t.Errorf("%q logged at %s, want debug", msg, entry.level)
+		}
+
+		u := fmt.Sprint(entry.args["url"])
+		if strings.Contains(u, "secret") || !strings.Contains(u, "key="+redactedValue) {
+			t.Errorf("%q url = %s, want the key redacted", msg, u)
+		}
+	}
+}
+
+func TestLoggerFailedRequestLevel(t *testing.T) {
+	client, logger := newLoggedClient(t, `{"returnCode":"010006","returnDesc":"OVER_QUERY_LIMIT"}`,
+		WithLogLevel(LogLevelInfo), WithErrorLogLevel(LogLevelWarn))
+
+	if _, err := client.Directions(context.Background(), directionsRequest()); err == nil {
+		t.Fatal("want an error for a non-OK return code")
+	}
+
+	started, _ := logger.find("map-kit: request started")
+	if started.level != "info" {
+		t.Errorf("start logged at %s, want info", started.level)
+	}
+
+	finished, ok := logger.find("map-kit: request finished")
+	if !ok {
+		t.Fatal("end of request not logged")
+	}
+	if finished.level != "warn" {
+		t.Errorf("failure logged at %s, want warn", finished.level)
+	}
+	if finished.args["returnCode"] != "010006" || finished.args["returnDesc"] != "OVER_QUERY_LIMIT" {
+		t.Errorf("args = %v, want the return code and description", finished.args)
+	}
+}
+
+func TestLoggerBodies(t *testing.T) {
+	client, logger := newLoggedClient(t, `{"returnCode":"0","returnDesc":"OK","routes":[]}`, WithLogBodies(16))
+
+	if _, err := client.Directions(context.Background(), directionsRequest()); err != nil {
+		t.Fatal(err)
+	}
+
+	request, ok := logger.find("map-kit: request body")
+	if !ok {
+		t.Fatal("request body not logged")
+	}
+	if body := fmt.Sprint(request.args["body"]); body != `{"origin":{"lng"...(truncated)` {
+		t.Errorf("request body = %s", body)
+	}
+
+	if _, ok := logger.find("map-kit: response body"); !ok {
+		t.Error("response body not logged")
+	}
+}
+
+func TestLoggerDisabled(t *testing.T) {
+	client, err := NewClient(WithAPIKey("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a logger nothing is formatted, so a nil request must not panic.
+	client.logRequestEnd(nil, time.Now(), 0, nil, nil)
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://example.com/a?key=secret&x=1", "https://example.com/a?key=REDACTED&x=1"},
+		{"https://example.com/a?x=1", "https://example.com/a?x=1"},
+		{"https://example.com/a", "https://example.com/a"},
+	}
+
+	for _, test := range tests {
+		u, _ := url.Parse(test.in)
+		if got := redactURL(u); got != test.want {
+			t.Errorf("redactURL(%s) = %s, want %s", test.in, got, test.want)
+		}
+	}
+
+	if got := redactURL(nil); got != "" {
+		t.Errorf("redactURL(nil) = %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Package mock provides mock implementations of the go-huawei service
+// interfaces that record calls and return programmed results.
+package mock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+var _ go_huawei.DirectionsService = (*DirectionsService)(nil)
+
+// DirectionsCall records a single call to DirectionsService.Directions.
+type DirectionsCall struct {
+	Ctx               context.Context
+	DirectionsRequest *go_huawei.DirectionsRequest
+}
+
+// DirectionsResult is a programmed result of DirectionsService.Directions.
+type DirectionsResult struct {
+	Routes []go_huawei.Route
+	Err    error
+}
+
+// DirectionsService is a mock implementation of go_huawei.DirectionsService.
+//
+// Results are taken from the queue filled by Return in order. Once the queue is
+// empty DirectionsFunc is called if set, otherwise the zero result is returned.
+type DirectionsService struct {
+	// DirectionsFunc computes the result once the programmed results are used up.
+	DirectionsFunc func(ctx context.Context, directionsRequest *go_huawei.DirectionsRequest) ([]go_huawei.Route, error)
+
+	mu      sync.Mutex
+	results []DirectionsResult
+	calls   []DirectionsCall
+}
+
+// Return programs the result of the next call. It may be chained to program
+// several calls.
+func (m *DirectionsService) Return(routes []go_huawei.Route, err error) *DirectionsService {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results = append(m.results, DirectionsResult{Routes: routes, Err: err})
+	return m
+}
+
+// Directions records the call and returns the next programmed result.
+func (m *DirectionsService) Directions(ctx context.Context, directionsRequest *go_huawei.DirectionsRequest) ([]go_huawei.Route, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, DirectionsCall{Ctx: ctx, DirectionsRequest: directionsRequest})
+
+	if len(m.results) > 0 {
+		result := m.results[0]
+		m.results = m.results[1:]
+		m.mu.Unlock()
+
+		return result.Routes, result.Err
+	}
+
+	fn := m.DirectionsFunc
+	m.mu.Unlock()
+
+	if fn != nil {
+		return fn(ctx, directionsRequest)
+	}
+
+	return nil, nil
+}
+
+// DirectionsCalls returns the calls made so far.
+func (m *DirectionsService) DirectionsCalls() []DirectionsCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := make([]DirectionsCall, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// Reset drops programmed results and recorded calls.
+func (m *DirectionsService) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results = nil
+	m.calls = nil
+}
@@ -0,0 +1,155 @@
+package mock_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/mock"
+)
+
+var (
+	kyiv    = &go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234}
+	brovary = &go_huawei.Coordinate{Lat: 50.5113, Lng: 30.7903}
+)
+
+func routes(roadName string) []go_huawei.Route {
+	return []go_huawei.Route{{Paths: []go_huawei.Path{{Steps: []go_huawei.Step{{RoadName: roadName}}}}}}
+}
+
+func roadName(routes []go_huawei.Route) string {
+	if len(routes) == 0 {
+		return ""
+	}
+	return routes[0].Paths[0].Steps[0].RoadName
+}
+
+func TestDirectionsServiceQueue(t *testing.T) {
+	errDown := errors.New("server down")
+	m := new(mock.DirectionsService).
+		Return(routes("first"), nil).
+		Return(nil, errDown).
+		Return(routes("third"), nil)
+	m.DirectionsFunc = func(context.Context, *go_huawei.DirectionsRequest) ([]go_huawei.Route, error) {
+		return routes("func"), nil
+	}
+
+	ctx := context.Background()
+	want := []struct {
+		road string
+		err  error
+	}{
+		{"first", nil},
+		{"", errDown},
+		{"third", nil},
+		{"func", nil},
+		{"func", nil},
+	}
+
+	for i, w := range want {
+		got, err := m.Directions(ctx, &go_huawei.DirectionsRequest{Origin: kyiv, Destination: brovary})
+		if roadName(got) != w.road || err != w.err {
+			t.Errorf("call %d = %q, %v; want %q, %v", i, roadName(got), err, w.road, w.err)
+		}
+	}
+}
+
+func TestDirectionsServiceEmpty(t *testing.T) {
+	var m mock.DirectionsService
+
+	got, err := m.Directions(context.Background(), &go_huawei.DirectionsRequest{})
+	if got != nil || err != nil {
+		t.Errorf("unprogrammed call = %v, %v; want the zero result", got, err)
+	}
+	if len(m.DirectionsCalls()) != 1 {
+		t.Errorf("unprogrammed call was not recorded")
+	}
+}
+
+func TestDirectionsServiceCalls(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "trace")
+	first := &go_huawei.DirectionsRequest{Origin: kyiv, Destination: brovary}
+	second := &go_huawei.DirectionsRequest{Origin: brovary, Destination: kyiv, Waypoints: []*go_huawei.Coordinate{kyiv}}
+
+	var m mock.DirectionsService
+	m.Directions(ctx, first)
+	m.Directions(context.Background(), second)
+
+	calls := m.DirectionsCalls()
+	if len(calls) != 2 {
+		t.Fatalf("recorded %d calls, want 2", len(calls))
+	}
+	if calls[0].Ctx.Value(key{}) != "trace" || calls[0].DirectionsRequest != first {
+		t.Errorf("first call = %+v", calls[0])
+	}
+	if calls[1].DirectionsRequest != second {
+		t.Errorf("second call = %+v", calls[1])
+	}
+
+	// The returned slice is a copy.
+	calls[0].DirectionsRequest = nil
+	if m.DirectionsCalls()[0].DirectionsRequest != first {
+		t.Error("DirectionsCalls shares its slice with the mock")
+	}
+}
+
+func TestDirectionsServiceReset(t *testing.T) {
+	m := new(mock.DirectionsService).Return(routes("stale"), nil)
+	m.Directions(context.Background(), &go_huawei.DirectionsRequest{})
+	m.Return(routes("queued"), nil)
+
+	m.Reset()
+
+	if calls := m.DirectionsCalls(); len(calls) != 0 {
+		t.Errorf("calls after Reset = %v", calls)
+	}
+	if got, err := m.Directions(context.Background(), &go_huawei.DirectionsRequest{}); got != nil || err != nil {
+		t.Errorf("result after Reset = %q, %v; want the queue dropped", roadName(got), err)
+	}
+
+	m.Return(routes("fresh"), nil)
+	if got, _ := m.Directions(context.Background(), &go_huawei.DirectionsRequest{}); roadName(got) != "fresh" {
+		t.Errorf("result programmed after Reset = %q", roadName(got))
+	}
+}
+
+func TestDirectionsServiceConcurrent(t *testing.T) {
+	const calls = 64
+
+	m := new(mock.DirectionsService)
+	for i := 0; i < calls/2; i++ {
+		m.Return(routes("queued"), nil)
+	}
+	m.DirectionsFunc = func(context.Context, *go_huawei.DirectionsRequest) ([]go_huawei.Route, error) {
+		return routes("func"), nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		counts = map[string]int{}
+	)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, _ := m.Directions(context.Background(), &go_huawei.DirectionsRequest{})
+			m.DirectionsCalls()
+
+			mu.Lock()
+			counts[roadName(got)]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if counts["queued"] != calls/2 || counts["func"] != calls/2 {
+		t.Errorf("results = %v, want each queued result used once", counts)
+	}
+	if n := len(m.DirectionsCalls()); n != calls {
+		t.Errorf("recorded %d calls, want %d", n, calls)
+	}
+}
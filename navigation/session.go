@@ -0,0 +1,431 @@
+// Package navigation follows a vehicle along a planned Path: it tracks progress
+// from GPS fixes, reports the current and next maneuver and detects arrival and
+// leaving the route.
+package navigation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+const (
+	defaultOffRouteDistance = 50
+	defaultOnRouteDistance  = 25
+	defaultOffRouteFixes    = 3
+	defaultApproachDistance = 200
+	defaultArrivalDistance  = 30
+
+	defaultRerouteBackoff    = 5 * time.Second
+	defaultMaxRerouteBackoff = 2 * time.Minute
+)
+
+// ErrNoRoute is returned when a re-route request yields no usable path.
+var ErrNoRoute = errors.New("navigation: re-route returned no path")
+
+// EventType identifies what happened during a Session update.
+type EventType int
+
+const (
+	// EventApproachingManeuver is emitted once per maneuver when it gets within
+	// the approach distance.
+	EventApproachingManeuver = EventType(0)
+	// EventArrived is emitted once when the destination is reached.
+	EventArrived = EventType(1)
+	// EventOffRoute is emitted when fixes stay away from the path.
+	EventOffRoute = EventType(2)
+	// EventBackOnRoute is emitted when fixes return to the path after
+	// EventOffRoute.
+	EventBackOnRoute = EventType(3)
+	// EventRerouted is emitted when the session switched to a new path.
+	EventRerouted = EventType(4)
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventApproachingManeuver:
+		return "approaching maneuver"
+	case EventArrived:
+		return "arrived"
+	case EventOffRoute:
+		return "off route"
+	case EventBackOnRoute:
+		return "back on route"
+	case EventRerouted:
+		return "rerouted"
+	}
+
+	return "unknown"
+}
+
+// Fix is a GPS position report.
+type Fix struct {
+	Location go_huawei.Coordinate
+	// Time of the fix. The time of the update is used when zero.
+	Time time.Time
+	// Accuracy is the horizontal accuracy of the fix, in metres. It widens the
+	// off-route threshold.
+	Accuracy float64
+}
+
+// Maneuver is a step of the path seen from the current position.
+type Maneuver struct {
+	StepIndex   int
+	Action      go_huawei.Action
+	RoadName    string
+	Instruction string
+	// Location is where the maneuver takes place.
+	Location go_huawei.Coordinate
+	// Distance from the current position to the maneuver along the route, in
+	// metres.
+	Distance float64
+}
+
+// Progress is the state of the session after the last fix.
+type Progress struct {
+	// Location is the fix snapped to the path.
+	Location go_huawei.Coordinate
+	// Offset is the cross-track distance of the fix from the path, in metres,
+	// positive right of the direction of travel.
+	Offset float64
+
+	DistanceTravelled float64
+	DistanceRemaining float64
+	DurationRemaining time.Duration
+	ETA               time.Time
+
+	// Current is the maneuver of the step being travelled.
+	Current Maneuver
+	// Next is the upcoming maneuver. At the last step it is the arrival at the
+	// end of the path.
+	Next Maneuver
+
+	OffRoute bool
+	Arrived  bool
+}
+
+// Event is a notable change reported by Session.Update.
+type Event struct {
+	Type     EventType
+	Progress Progress
+	// Maneuver is the maneuver being approached, for EventApproachingManeuver.
+	Maneuver Maneuver
+	// Routes are the routes returned by the re-route, for EventRerouted.
+	Routes []go_huawei.Route
+}
+
+// Option is the type of constructor options for NewSession(...).
+type Option func(*Session)
+
+// WithOffRouteThreshold configures the hysteresis of off-route detection: the
+// session goes off route after fixes farther than offRoute metres from the path
+// for the given number of consecutive fixes, and comes back once a fix is within
+// onRoute metres. Defaults are 50 m, 3 fixes and 25 m.
+func WithOffRouteThreshold(offRoute float64, fixes int, onRoute float64) Option {
+	return func(s *Session) {
+		s.offRouteDistance = offRoute
+		s.offRouteFixes = fixes
+		s.onRouteDistance = onRoute
+	}
+}
+
+// WithApproachDistance configures how close to a maneuver, in metres,
+// EventApproachingManeuver is emitted. Default is 200 m.
+func WithApproachDistance(meters float64) Option {
+	return func(s *Session) {
+		s.approachDistance = meters
+	}
+}
+
+// WithArrivalDistance configures how close to the end of the path, in metres,
+// the destination counts as reached. Default is 30 m.
+func WithArrivalDistance(meters float64) Option {
+	return func(s *Session) {
+		s.arrivalDistance = meters
+	}
+}
+
+// WithRerouter makes the session request a new route from the current location
+// when it goes off route. The request is a copy of template with the fix as
+// origin and without the waypoints already passed; the destination defaults to
+// the end of the current path.
+func WithRerouter(service go_huawei.DirectionsService, template *go_huawei.DirectionsRequest) Option {
+	return func(s *Session) {
+		s.rerouter = service
+		s.template = template
+	}
+}
+
+// WithRerouteBackoff configures how long the session waits before retrying a
+// failed re-route while still off route. The wait starts at initial and doubles
+// after every failure up to max. Defaults are 5 s and 2 min.
+func WithRerouteBackoff(initial, max time.Duration) Option {
+	return func(s *Session) {
+		s.rerouteBackoff = initial
+		s.maxRerouteBackoff = max
+	}
+}
+
+// Session tracks travel along a path. It is not safe for concurrent use.
+type Session struct {
+	path      *go_huawei.Path
+	projector *go_huawei.PathProjector
+	// stepStarts[i] is the route distance at the start of step i.
+	stepStarts []float64
+
+	offRouteDistance float64
+	onRouteDistance  float64
+	offRouteFixes    int
+	approachDistance float64
+	arrivalDistance  float64
+
+	rerouter go_huawei.DirectionsService
+	template *go_huawei.DirectionsRequest
+	// waypoints are the waypoints of template not passed yet.
+	waypoints []*go_huawei.Coordinate
+
+	rerouteBackoff    time.Duration
+	maxRerouteBackoff time.Duration
+	rerouteFailures   int
+	nextReroute       time.Time
+
+	progress     Progress
+	farFixes     int
+	approached   map[int]bool
+	arrivedFired bool
+}
+
+// NewSession starts following the path.
+func NewSession(path *go_huawei.Path, options ...Option) (*Session, error) {
+	if path == nil {
+		return nil, errors.New("navigation: path missing")
+	}
+
+	s := &Session{
+		offRouteDistance:  defaultOffRouteDistance,
+		onRouteDistance:   defaultOnRouteDistance,
+		offRouteFixes:     defaultOffRouteFixes,
+		approachDistance:  defaultApproachDistance,
+		arrivalDistance:   defaultArrivalDistance,
+		rerouteBackoff:    defaultRerouteBackoff,
+		maxRerouteBackoff: defaultMaxRerouteBackoff,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.template != nil {
+		s.waypoints = s.template.Waypoints
+	}
+
+	s.setPath(path)
+	return s, nil
+}
+
+// Path returns the path being followed, which changes after a re-route.
+func (s *Session) Path() *go_huawei.Path {
+	return s.path
+}
+
+// Progress returns the progress after the last fix.
+func (s *Session) Progress() Progress {
+	return s.progress
+}
+
+// Update feeds a fix to the session and returns the events it caused. An error
+// is returned only if a re-route was attempted and failed; the session then
+// keeps following the current path and retries on later off-route fixes,
+// backing off after each failure.
+func (s *Session) Update(ctx context.Context, fix Fix) ([]Event, error) {
+	if fix.Time.IsZero() {
+		fix.Time = time.Now()
+	}
+
+	projection, ok := s.projector.Project(fix.Location)
+	if !ok {
+		return nil, nil
+	}
+
+	wasOffRoute := s.progress.OffRoute
+	s.updateProgress(projection, fix)
+
+	var events []Event
+
+	distance := math.Abs(projection.Offset)
+	switch {
+	case !wasOffRoute && distance > s.offRouteDistance+fix.Accuracy:
+		s.farFixes++
+		if s.farFixes >= s.offRouteFixes {
+			s.progress.OffRoute = true
+			events = append(events, Event{Type: EventOffRoute, Progress: s.progress})
+
+			if s.rerouter != nil {
+				return s.tryReroute(ctx, fix, events)
+			}
+		}
+	case !wasOffRoute:
+		s.farFixes = 0
+	case distance <= s.onRouteDistance+fix.Accuracy:
+		s.farFixes = 0
+		s.rerouteFailures = 0
+		s.progress.OffRoute = false
+		events = append(events, Event{Type: EventBackOnRoute, Progress: s.progress})
+	default:
+		s.progress.OffRoute = true
+		if s.rerouter != nil && s.rerouteFailures > 0 && !fix.Time.Before(s.nextReroute) {
+			return s.tryReroute(ctx, fix, events)
+		}
+	}
+
+	if s.progress.OffRoute {
+		return events, nil
+	}
+
+	next := s.progress.Next
+	if next.Distance <= s.approachDistance && !s.approached[next.StepIndex] && next.StepIndex < len(s.path.Steps) {
+		s.approached[next.StepIndex] = true
+		events = append(events, Event{Type: EventApproachingManeuver, Progress: s.progress, Maneuver: next})
+	}
+
+	if !s.arrivedFired && s.progress.DistanceRemaining <= s.arrivalDistance {
+		s.arrivedFired = true
+		s.progress.Arrived = true
+		events = append(events, Event{Type: EventArrived, Progress: s.progress})
+	}
+
+	return events, nil
+}
+
+func (s *Session) setPath(path *go_huawei.Path) {
+	s.path = path
+	s.projector = go_huawei.NewPathProjector(path)
+	s.approached = make(map[int]bool)
+	s.farFixes = 0
+	s.rerouteFailures = 0
+	s.arrivedFired = false
+	s.progress = Progress{}
+
+	s.stepStarts = make([]float64, len(path.Steps)+1)
+	for i, step := range path.Steps {
+		distance := step.Distance
+		if distance <= 0 {
+			distance = go_huawei.PathLength(step.Polyline)
+		}
+		s.stepStarts[i+1] = s.stepStarts[i] + distance
+	}
+}
+
+func (s *Session) updateProgress(projection go_huawei.Projection, fix Fix) {
+	offRoute := s.progress.OffRoute
+
+	s.progress = Progress{
+		Location:          projection.Point,
+		Offset:            projection.Offset,
+		DistanceTravelled: projection.DistanceAlong,
+		DistanceRemaining: projection.DistanceRemaining,
+		DurationRemaining: projection.DurationRemaining,
+		ETA:               fix.Time.Add(projection.DurationRemaining),
+		Current:           s.maneuver(projection.StepIndex, projection.DistanceAlong),
+		Next:              s.maneuver(projection.StepIndex+1, projection.DistanceAlong),
+		OffRoute:          offRoute,
+		Arrived:           s.arrivedFired,
+	}
+}
+
+// maneuver describes step i seen from the given route distance. Index
+// len(Steps) is the arrival at the end of the path.
+func (s *Session) maneuver(i int, travelled float64) Maneuver {
+	steps := s.path.Steps
+	if i >= len(steps) {
+		return Maneuver{
+			StepIndex: len(steps),
+			Action:    go_huawei.End,
+			Location:  s.path.EndLocation,
+			Distance:  math.Max(0, s.stepStarts[len(steps)]-travelled),
+		}
+	}
+
+	step := steps[i]
+	return Maneuver{
+		StepIndex:   i,
+		Action:      step.Action,
+		RoadName:    step.RoadName,
+		Instruction: step.Instruction,
+		Location:    step.StartLocation,
+		Distance:    math.Max(0, s.stepStarts[i]-travelled),
+	}
+}
+
+// tryReroute re-routes from the fix and, on failure, schedules the next attempt.
+func (s *Session) tryReroute(ctx context.Context, fix Fix, events []Event) ([]Event, error) {
+	rerouted, err := s.reroute(ctx, fix)
+	if err != nil {
+		backoff := s.rerouteBackoff
+		for i := 0; i < s.rerouteFailures && backoff < s.maxRerouteBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > s.maxRerouteBackoff {
+			backoff = s.maxRerouteBackoff
+		}
+
+		s.rerouteFailures++
+		s.nextReroute = fix.Time.Add(backoff)
+		return events, err
+	}
+
+	return append(events, rerouted), nil
+}
+
+// remainingWaypoints returns the waypoints lying beyond the current position
+// along the path.
+func (s *Session) remainingWaypoints() []*go_huawei.Coordinate {
+	var remaining []*go_huawei.Coordinate
+	for _, waypoint := range s.waypoints {
+		if waypoint != nil && s.path.DistanceAt(*waypoint) > s.progress.DistanceTravelled {
+			remaining = append(remaining, waypoint)
+		}
+	}
+
+	return remaining
+}
+
+func (s *Session) reroute(ctx context.Context, fix Fix) (Event, error) {
+	req := go_huawei.DirectionsRequest{}
+	if s.template != nil {
+		req = *s.template
+	}
+
+	origin := fix.Location
+	req.Origin = &origin
+	if req.Destination == nil {
+		destination := s.path.EndLocation
+		req.Destination = &destination
+	}
+
+	// Waypoints already passed are not revisited, and alternatives are of no
+	// use while driving.
+	waypoints := s.remainingWaypoints()
+	req.Waypoints = waypoints
+	req.Alternatives = false
+
+	routes, err := s.rerouter.Directions(ctx, &req)
+	if err != nil {
+		return Event{}, err
+	}
+
+	if len(routes) == 0 || len(routes[0].Paths) == 0 {
+		return Event{}, ErrNoRoute
+	}
+
+	s.waypoints = waypoints
+	s.setPath(&routes[0].Paths[0])
+	if projection, ok := s.projector.Project(fix.Location); ok {
+		s.updateProgress(projection, fix)
+	}
+
+	return Event{Type: EventRerouted, Progress: s.progress, Routes: routes}, nil
+}
@@ -0,0 +1,136 @@
+package navigation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+// rerouter answers re-routes with the offline router after failing a given
+// number of times, and records the requests.
+type rerouter struct {
+	failures int
+	requests []go_huawei.DirectionsRequest
+}
+
+func (r *rerouter) Directions(_ context.Context, req *go_huawei.DirectionsRequest) ([]go_huawei.Route, error) {
+	r.requests = append(r.requests, *req)
+	if len(r.requests) <= r.failures {
+		return nil, errors.New("upstream unavailable")
+	}
+
+	return huaweitest.NewRouter().Route(req), nil
+}
+
+// A route due north with two waypoints, 1.1 km apart.
+var (
+	start     = go_huawei.Coordinate{Lat: 50.00, Lng: 36}
+	firstStop = go_huawei.Coordinate{Lat: 50.01, Lng: 36}
+	lastStop  = go_huawei.Coordinate{Lat: 50.02, Lng: 36}
+	end       = go_huawei.Coordinate{Lat: 50.03, Lng: 36}
+)
+
+func newTestSession(t *testing.T, r *rerouter, options ...Option) *Session {
+	template := &go_huawei.DirectionsRequest{
+		Origin:      &start,
+		Destination: &end,
+		Waypoints:   []*go_huawei.Coordinate{&firstStop, &lastStop},
+	}
+	routes := huaweitest.NewRouter().Route(template)
+
+	s, err := NewSession(&routes[0].Paths[0], append([]Option{WithRerouter(r, template)}, options...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// driveOffRoute feeds fixes 700 m east of the route, between the waypoints,
+// one second apart from t0.
+func driveOffRoute(s *Session, t0 time.Time, seconds ...int) ([]Event, error) {
+	var events []Event
+	for _, second := range seconds {
+		fix := Fix{Location: go_huawei.Coordinate{Lat: 50.015, Lng: 36.01}, Time: t0.Add(time.Duration(second) * time.Second)}
+
+		e, err := s.Update(context.Background(), fix)
+		events = append(events, e...)
+		if err != nil {
+			return events, err
+		}
+	}
+
+	return events, nil
+}
+
+func TestNewSessionWithoutPath(t *testing.T) {
+	if s, err := NewSession(nil); err == nil {
+		t.Errorf("NewSession(nil) = %v, want an error", s)
+	}
+}
+
+func TestRerouteKeepsRemainingWaypoints(t *testing.T) {
+	r := &rerouter{}
+	s := newTestSession(t, r)
+
+	events, err := driveOffRoute(s, time.Unix(0, 0), 0, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first fix may approach a maneuver before the session goes off route.
+	if n := len(events); n < 2 || events[n-2].Type != EventOffRoute || events[n-1].Type != EventRerouted {
+		t.Fatalf("events = %v, want off route and rerouted last", events)
+	}
+
+	waypoints := r.requests[0].Waypoints
+	if len(waypoints) != 1 || *waypoints[0] != lastStop {
+		t.Errorf("re-route waypoints = %v, want only the stop not reached yet", waypoints)
+	}
+	if r.requests[0].Origin.Lng != 36.01 || *r.requests[0].Destination != end {
+		t.Errorf("re-route from %v to %v", r.requests[0].Origin, r.requests[0].Destination)
+	}
+}
+
+func TestRerouteRetriesWithBackoff(t *testing.T) {
+	r := &rerouter{failures: 2}
+	s := newTestSession(t, r, WithRerouteBackoff(5*time.Second, 8*time.Second))
+	t0 := time.Unix(0, 0)
+
+	if _, err := driveOffRoute(s, t0, 0, 1, 2); err == nil {
+		t.Fatal("first re-route did not fail")
+	}
+	if !s.Progress().OffRoute {
+		t.Fatal("session is not off route after a failed re-route")
+	}
+
+	// The second attempt waits 5 s, the third 8 s instead of 10 s.
+	for _, step := range []struct {
+		second   int
+		attempts int
+		failed   bool
+	}{
+		{second: 3, attempts: 1},
+		{second: 7, attempts: 2, failed: true},
+		{second: 14, attempts: 2},
+		{second: 15, attempts: 3},
+	} {
+		events, err := driveOffRoute(s, t0, step.second)
+		if len(r.requests) != step.attempts {
+			t.Fatalf("after %d s: %d re-route attempts, want %d", step.second, len(r.requests), step.attempts)
+		}
+		if (err != nil) != step.failed {
+			t.Fatalf("after %d s: err = %v", step.second, err)
+		}
+		if step.attempts == 3 && (len(events) != 1 || events[0].Type != EventRerouted) {
+			t.Errorf("after %d s: events = %v, want rerouted", step.second, events)
+		}
+	}
+
+	if len(r.requests[2].Waypoints) != 1 {
+		t.Errorf("retried re-route waypoints = %v", r.requests[2].Waypoints)
+	}
+}
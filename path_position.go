@@ -0,0 +1,342 @@
+package go_huawei
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// stepGeometry is the polyline of a step with its cumulative lengths and its
+// place on the path.
+type stepGeometry struct {
+	polyline []Coordinate
+	// cumulative[i] is the geometric length of the polyline up to vertex i.
+	cumulative []float64
+
+	// distance and duration are the values reported for the step; distance
+	// falls back to the geometric length when missing.
+	distance float64
+	duration float64
+
+	startDistance float64
+	startDuration float64
+
+	// bounds is the bounding box of the polyline, which may cross the
+	// antimeridian.
+	bounds CoordinateBounds
+}
+
+func (s *stepGeometry) length() float64 {
+	return s.cumulative[len(s.cumulative)-1]
+}
+
+// pointAt returns the point the given geometric distance along the step.
+func (s *stepGeometry) pointAt(along float64) Coordinate {
+	if along <= 0 || len(s.polyline) == 1 {
+		return s.polyline[0]
+	}
+
+	if along >= s.length() {
+		return s.polyline[len(s.polyline)-1]
+	}
+
+	i := sort.SearchFloat64s(s.cumulative, along)
+	segment := s.cumulative[i] - s.cumulative[i-1]
+	if segment == 0 {
+		return s.polyline[i]
+	}
+
+	return s.polyline[i-1].Interpolate(s.polyline[i], (along-s.cumulative[i-1])/segment)
+}
+
+// pathGeometry models travel along a path: step polylines give the shape while
+// step distances and durations give the speed on each step.
+type pathGeometry struct {
+	steps    []stepGeometry
+	distance float64
+	duration float64
+}
+
+func newPathGeometry(p *Path) *pathGeometry {
+	g := &pathGeometry{}
+	if p == nil {
+		return g
+	}
+
+	for _, step := range p.Steps {
+		polyline := RemoveDuplicates(step.Polyline)
+		if len(polyline) == 0 {
+			polyline = RemoveDuplicates([]Coordinate{step.StartLocation, step.EndLocation})
+		}
+
+		s := stepGeometry{
+			polyline:      polyline,
+			cumulative:    make([]float64, len(polyline)),
+			distance:      step.Distance,
+			duration:      step.Duration,
+			startDistance: g.distance,
+			startDuration: g.duration,
+		}
+		for i := 1; i < len(polyline); i++ {
+			s.cumulative[i] = s.cumulative[i-1] + polyline[i-1].DistanceTo(polyline[i])
+		}
+		s.bounds = NewCoordinateBounds(polyline)
+		if s.distance <= 0 {
+			s.distance = s.length()
+		}
+
+		g.steps = append(g.steps, s)
+		g.distance += s.distance
+		g.duration += s.duration
+	}
+
+	return g
+}
+
+// atDistance returns the position and step index after travelling the given
+// route distance.
+func (g *pathGeometry) atDistance(distance float64) (Coordinate, int) {
+	if len(g.steps) == 0 {
+		return Coordinate{}, -1
+	}
+
+	for i := range g.steps {
+		s := &g.steps[i]
+		if distance < s.startDistance+s.distance || i == len(g.steps)-1 {
+			fraction := 1.0
+			if s.distance > 0 {
+				fraction = math.Max(0, math.Min(1, (distance-s.startDistance)/s.distance))
+			}
+			return s.pointAt(fraction * s.length()), i
+		}
+	}
+
+	return Coordinate{}, -1
+}
+
+// atDuration returns the position and step index after travelling for the given
+// number of seconds.
+func (g *pathGeometry) atDuration(seconds float64) (Coordinate, int) {
+	if len(g.steps) == 0 {
+		return Coordinate{}, -1
+	}
+
+	for i := range g.steps {
+		s := &g.steps[i]
+		if seconds < s.startDuration+s.duration || i == len(g.steps)-1 {
+			fraction := 1.0
+			if s.duration > 0 {
+				fraction = math.Max(0, math.Min(1, (seconds-s.startDuration)/s.duration))
+			}
+			return s.pointAt(fraction * s.length()), i
+		}
+	}
+
+	return Coordinate{}, -1
+}
+
+// pathLocation is the point of a path closest to a coordinate.
+type pathLocation struct {
+	point   Coordinate
+	step    int
+	segment int
+	// fraction is the position of point on the segment.
+	fraction float64
+	// along is the geometric distance from the start of the step.
+	along float64
+	// offset is the distance from the coordinate to point, in metres.
+	offset float64
+	// lateral is offset signed positive when the coordinate lies right of the
+	// direction of travel.
+	lateral float64
+}
+
+// minDistance returns a lower bound of the distance from c to the step, in
+// metres, using its bounding box.
+func (s *stepGeometry) minDistance(c Coordinate, projection localProjection) float64 {
+	b := s.bounds
+	if b.Contains(c) {
+		return 0
+	}
+
+	lat := math.Max(b.Southwest.Lat, math.Min(b.Northeast.Lat, c.Lat))
+	lng := c.Lng
+	if lngDistanceEast(b.Southwest.Lng, c.Lng) > b.lngSpan() {
+		// Outside the longitudes of the box: clamp to the nearer edge, which
+		// may lie across the antimeridian.
+		lng = b.Southwest.Lng
+		if lngDistanceEast(b.Northeast.Lng, c.Lng) < lngDistanceEast(c.Lng, b.Southwest.Lng) {
+			lng = b.Northeast.Lng
+		}
+	}
+
+	p := projection.project(Coordinate{Lat: lat, Lng: lng})
+	// The projection is centred on c, where its scale is exact; shrink the
+	// estimate slightly to stay a lower bound across the box.
+	return math.Hypot(p.x, p.y) * 0.99
+}
+
+// routeDistance returns the route distance travelled at the location.
+func (g *pathGeometry) routeDistance(l pathLocation) float64 {
+	s := &g.steps[l.step]
+	if s.length() == 0 {
+		return s.startDistance
+	}
+
+	return s.startDistance + l.along/s.length()*s.distance
+}
+
+// routeDuration returns the seconds travelled at the location.
+func (g *pathGeometry) routeDuration(l pathLocation) float64 {
+	s := &g.steps[l.step]
+	if s.length() == 0 {
+		return s.startDuration
+	}
+
+	return s.startDuration + l.along/s.length()*s.duration
+}
+
+type stepCandidate struct {
+	index       int
+	minDistance float64
+}
+
+// leftOfStop reports whether the origin of the projection lies left of the
+// direction of travel through step i, a step with a single vertex. The
+// direction runs from the vertex before the step to the one after it; a path
+// without another vertex has none and the origin counts as right of it.
+func (g *pathGeometry) leftOfStop(i int, projection localProjection) bool {
+	stop := g.steps[i].polyline[0]
+	before, after := stop, stop
+	for j := i - 1; j >= 0 && before == stop; j-- {
+		polyline := g.steps[j].polyline
+		for k := len(polyline) - 1; k >= 0; k-- {
+			if polyline[k] != stop {
+				before = polyline[k]
+				break
+			}
+		}
+	}
+	for j := i + 1; j < len(g.steps) && after == stop; j++ {
+		for _, c := range g.steps[j].polyline {
+			if c != stop {
+				after = c
+				break
+			}
+		}
+	}
+
+	a, b, p := projection.project(before), projection.project(after), projection.project(stop)
+	return (b.x-a.x)*(-p.y)-(b.y-a.y)*(-p.x) > 0
+}
+
+// locateSegments finds the closest point to c on the segments of the given
+// steps. Steps with a single vertex count as a point, passed in the direction
+// of the path around them.
+func (g *pathGeometry) locateSegments(c Coordinate, fromStep, toStep int) (pathLocation, bool) {
+	projection := localProjection{origin: c, cosLat: math.Cos(toRadians(c.Lat))}
+	best := pathLocation{step: -1, offset: math.Inf(1)}
+
+	// Visit steps nearest first by their bounding boxes so that the remaining
+	// ones can be skipped once they cannot be closer than the best match.
+	toStep = int(math.Min(float64(toStep), float64(len(g.steps))))
+	candidates := make([]stepCandidate, 0, toStep-fromStep)
+	for i := fromStep; i < toStep; i++ {
+		candidates = append(candidates, stepCandidate{index: i, minDistance: g.steps[i].minDistance(c, projection)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].minDistance < candidates[j].minDistance
+	})
+
+	for _, candidate := range candidates {
+		if candidate.minDistance > best.offset {
+			break
+		}
+
+		i := candidate.index
+		s := &g.steps[i]
+		if len(s.polyline) == 1 {
+			if d := c.DistanceTo(s.polyline[0]); d < best.offset {
+				best = pathLocation{point: s.polyline[0], step: i, offset: d, lateral: d}
+				if g.leftOfStop(i, projection) {
+					best.lateral = -d
+				}
+			}
+			continue
+		}
+
+		for j := 1; j < len(s.polyline); j++ {
+			a, b := projection.project(s.polyline[j-1]), projection.project(s.polyline[j])
+			t := segmentFraction(planarPoint{}, a, b)
+			point := planarPoint{x: a.x + t*(b.x-a.x), y: a.y + t*(b.y-a.y)}
+
+			if d := math.Hypot(point.x, point.y); d < best.offset {
+				lateral := d
+				if (b.x-a.x)*(-a.y)-(b.y-a.y)*(-a.x) > 0 {
+					lateral = -d
+				}
+
+				segment := s.cumulative[j] - s.cumulative[j-1]
+				best = pathLocation{
+					step:     i,
+					segment:  j - 1,
+					fraction: t,
+					along:    s.cumulative[j-1] + t*segment,
+					offset:   d,
+					lateral:  lateral,
+				}
+			}
+		}
+	}
+
+	if best.step < 0 {
+		return best, false
+	}
+
+	if s := &g.steps[best.step]; len(s.polyline) > 1 {
+		best.point = s.polyline[best.segment].Interpolate(s.polyline[best.segment+1], best.fraction)
+	}
+
+	return best, true
+}
+
+// PositionAtDistance returns the position after travelling the given distance,
+// in metres, along the path, and the index of the step it lies on. Distances
+// follow the step distances reported by the API, spread along each step's
+// polyline. The position is clamped to the ends of the path; the step index is
+// -1 for a path without steps.
+func (p *Path) PositionAtDistance(distance float64) (Coordinate, int) {
+	return newPathGeometry(p).atDistance(distance)
+}
+
+// PositionAtTime returns the position after travelling for the elapsed time
+// along the path, and the index of the step it lies on. The speed on each step
+// is its Distance over its Duration. The position is clamped to the ends of the
+// path; the step index is -1 for a path without steps.
+func (p *Path) PositionAtTime(elapsed time.Duration) (Coordinate, int) {
+	return newPathGeometry(p).atDuration(elapsed.Seconds())
+}
+
+// DistanceAt returns the distance travelled, in metres, when reaching the point
+// of the path closest to c.
+func (p *Path) DistanceAt(c Coordinate) float64 {
+	g := newPathGeometry(p)
+	location, ok := g.locateSegments(c, 0, len(g.steps))
+	if !ok {
+		return 0
+	}
+
+	return g.routeDistance(location)
+}
+
+// TimeAt returns the time travelled when reaching the point of the path
+// closest to c.
+func (p *Path) TimeAt(c Coordinate) time.Duration {
+	g := newPathGeometry(p)
+	location, ok := g.locateSegments(c, 0, len(g.steps))
+	if !ok {
+		return 0
+	}
+
+	return time.Duration(g.routeDuration(location) * float64(time.Second))
+}
@@ -0,0 +1,160 @@
+package go_huawei
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// stopPath runs east along the equator from 0° to 0.05° in four steps: a
+// 0.02° drive of 100 s, a 30 s stop without length, a 0.01° step without
+// duration and a 0.02° drive of 100 s.
+func stopPath() *Path {
+	return &Path{
+		Distance: 0.05 * equatorDegree,
+		Duration: 230,
+		Steps: []Step{
+			{Polyline: []Coordinate{{}, {Lng: 0.01}, {Lng: 0.02}}, Distance: 0.02 * equatorDegree, Duration: 100},
+			{Polyline: []Coordinate{{Lng: 0.02}}, Duration: 30},
+			{Polyline: []Coordinate{{Lng: 0.02}, {Lng: 0.03}}, Distance: 0.01 * equatorDegree},
+			{Polyline: []Coordinate{{Lng: 0.03}, {Lng: 0.05}}, Distance: 0.02 * equatorDegree, Duration: 100},
+		},
+	}
+}
+
+// pacificPath runs east along the equator across the antimeridian in two
+// steps of 0.02°.
+func pacificPath() *Path {
+	return &Path{
+		Distance: 2 * 0.02 * equatorDegree,
+		Duration: 200,
+		Steps: []Step{
+			{Polyline: []Coordinate{{Lng: 179.98}, {Lng: 179.99}, {Lng: -180}}, Distance: 0.02 * equatorDegree, Duration: 100},
+			{Polyline: []Coordinate{{Lng: -180}, {Lng: -179.98}}, Distance: 0.02 * equatorDegree, Duration: 100},
+		},
+	}
+}
+
+func samePlace(a, b Coordinate) bool {
+	return a.DistanceTo(b) < 0.01
+}
+
+func TestPositionAtDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *Path
+		distance float64
+		want     Coordinate
+		step     int
+	}{
+		{"before the start", stopPath(), -5, Coordinate{}, 0},
+		{"middle of a step", stopPath(), 0.005 * equatorDegree, Coordinate{Lng: 0.005}, 0},
+		{"past a vertex", stopPath(), 0.015 * equatorDegree, Coordinate{Lng: 0.015}, 0},
+		{"skips the zero-length step", stopPath(), 0.02 * equatorDegree, Coordinate{Lng: 0.02}, 2},
+		{"zero-duration step", stopPath(), 0.025 * equatorDegree, Coordinate{Lng: 0.025}, 2},
+		{"last step", stopPath(), 0.04 * equatorDegree, Coordinate{Lng: 0.04}, 3},
+		{"beyond the end", stopPath(), 1e9, Coordinate{Lng: 0.05}, 3},
+		{"before the antimeridian", pacificPath(), 0.015 * equatorDegree, Coordinate{Lng: 179.995}, 0},
+		{"after the antimeridian", pacificPath(), 0.03 * equatorDegree, Coordinate{Lng: -179.99}, 1},
+	}
+
+	for _, test := range tests {
+		got, step := test.path.PositionAtDistance(test.distance)
+		if !samePlace(got, test.want) || step != test.step {
+			t.Errorf("%s: PositionAtDistance(%.1f) = %v on step %d, want %v on step %d", test.name, test.distance, &got, step, &test.want, test.step)
+		}
+	}
+}
+
+func TestPositionAtTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    *Path
+		elapsed time.Duration
+		want    Coordinate
+		step    int
+	}{
+		{"before the start", stopPath(), -time.Second, Coordinate{}, 0},
+		{"middle of a step", stopPath(), 50 * time.Second, Coordinate{Lng: 0.01}, 0},
+		{"start of the stop", stopPath(), 100 * time.Second, Coordinate{Lng: 0.02}, 1},
+		{"during the stop", stopPath(), 120 * time.Second, Coordinate{Lng: 0.02}, 1},
+		{"skips the zero-duration step", stopPath(), 130 * time.Second, Coordinate{Lng: 0.03}, 3},
+		{"last step", stopPath(), 180 * time.Second, Coordinate{Lng: 0.04}, 3},
+		{"beyond the end", stopPath(), time.Hour, Coordinate{Lng: 0.05}, 3},
+		{"across the antimeridian", pacificPath(), 150 * time.Second, Coordinate{Lng: -179.99}, 1},
+	}
+
+	for _, test := range tests {
+		got, step := test.path.PositionAtTime(test.elapsed)
+		if !samePlace(got, test.want) || step != test.step {
+			t.Errorf("%s: PositionAtTime(%v) = %v on step %d, want %v on step %d", test.name, test.elapsed, &got, step, &test.want, test.step)
+		}
+	}
+
+	var empty Path
+	if _, step := empty.PositionAtTime(time.Minute); step != -1 {
+		t.Errorf("path without steps: step = %d, want -1", step)
+	}
+	if _, step := (*Path)(nil).PositionAtDistance(10); step != -1 {
+		t.Errorf("nil path: step = %d, want -1", step)
+	}
+}
+
+func TestDistanceAndTimeAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *Path
+		c        Coordinate
+		distance float64
+		elapsed  time.Duration
+	}{
+		{"beside a step", stopPath(), Coordinate{Lat: 0.0001, Lng: 0.015}, 0.015 * equatorDegree, 75 * time.Second},
+		{"zero-duration step", stopPath(), Coordinate{Lat: -0.0001, Lng: 0.025}, 0.025 * equatorDegree, 130 * time.Second},
+		{"last step", stopPath(), Coordinate{Lng: 0.04}, 0.04 * equatorDegree, 180 * time.Second},
+		{"before the start", stopPath(), Coordinate{Lng: -1}, 0, 0},
+		{"beyond the end", stopPath(), Coordinate{Lng: 1}, 0.05 * equatorDegree, 230 * time.Second},
+		{"across the antimeridian", pacificPath(), Coordinate{Lat: 0.0001, Lng: -179.99}, 0.03 * equatorDegree, 150 * time.Second},
+	}
+
+	for _, test := range tests {
+		if got := test.path.DistanceAt(test.c); math.Abs(got-test.distance) > 0.01 {
+			t.Errorf("%s: DistanceAt(%v) = %.2f, want %.2f", test.name, &test.c, got, test.distance)
+		}
+		if got := test.path.TimeAt(test.c); math.Abs((got - test.elapsed).Seconds()) > 1e-3 {
+			t.Errorf("%s: TimeAt(%v) = %v, want %v", test.name, &test.c, got, test.elapsed)
+		}
+	}
+
+	if d := (*Path)(nil).DistanceAt(Coordinate{}); d != 0 {
+		t.Errorf("nil path: DistanceAt = %v", d)
+	}
+}
+
+func TestDistanceAtInverse(t *testing.T) {
+	for _, path := range []*Path{stopPath(), pacificPath()} {
+		// Times inside the stop of stopPath, 43% to 57% of the way, all map to
+		// one point and have no inverse.
+		for _, fraction := range []float64{0.1, 0.3, 0.4, 0.7, 0.9} {
+			// DistanceAt undoes PositionAtDistance and TimeAt undoes
+			// PositionAtTime.
+			distance := fraction * path.Distance
+			c, _ := path.PositionAtDistance(distance)
+			if got := path.DistanceAt(c); math.Abs(got-distance) > 0.01 {
+				t.Errorf("DistanceAt(PositionAtDistance(%.1f)) = %.2f", distance, got)
+			}
+
+			elapsed := time.Duration(fraction * path.Duration * float64(time.Second))
+			c, _ = path.PositionAtTime(elapsed)
+			if got := path.TimeAt(c); math.Abs((got - elapsed).Seconds()) > 1e-3 {
+				t.Errorf("TimeAt(PositionAtTime(%v)) = %v", elapsed, got)
+			}
+
+			// Both describe the same point of the path.
+			byDistance, _ := path.PositionAtDistance(path.DistanceAt(c))
+			byTime, _ := path.PositionAtTime(path.TimeAt(c))
+			if !samePlace(byDistance, byTime) {
+				t.Errorf("at %v: position by distance %v, by time %v", &c, &byDistance, &byTime)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package go_huawei
+
+import (
+	"math"
+	"time"
+)
+
+// defaultSearchRadius is how far, in metres, a point may lie from the path
+// before PathProjector searches beyond the neighbourhood of its last result.
+const defaultSearchRadius = 200
+
+// projectionWindow is the number of steps around the last result searched
+// first by PathProjector.
+const projectionWindow = 2
+
+// Projection is a point snapped to the closest location on a path.
+type Projection struct {
+	// Point is the closest location on the path polyline.
+	Point Coordinate
+	// StepIndex is the index of the step Point lies on.
+	StepIndex int
+
+	// DistanceAlong and DistanceRemaining are the route distances, in metres,
+	// travelled and left at Point.
+	DistanceAlong     float64
+	DistanceRemaining float64
+	// DurationRemaining is the travel time left at Point.
+	DurationRemaining time.Duration
+
+	// Offset is the cross-track distance from the projected coordinate to
+	// Point, in metres: positive right of the direction of travel, negative left.
+	// A step with a single vertex takes the direction of the path through it.
+	Offset float64
+}
+
+// PathProjector snaps points to a path. It precomputes the path geometry once
+// and searches around its previous result first, which makes it suitable for
+// per-second calls on long routes. It is not safe for concurrent use.
+type PathProjector struct {
+	geometry *pathGeometry
+	last     int
+
+	// SearchRadius is how far, in metres, a point may lie from the path near
+	// the previous result before the whole path is searched.
+	SearchRadius float64
+}
+
+// NewPathProjector returns a PathProjector for the path.
+func NewPathProjector(p *Path) *PathProjector {
+	return &PathProjector{
+		geometry:     newPathGeometry(p),
+		last:         -1,
+		SearchRadius: defaultSearchRadius,
+	}
+}
+
+// Project snaps c to the path. It returns false for a path without steps.
+func (pp *PathProjector) Project(c Coordinate) (Projection, bool) {
+	g := pp.geometry
+
+	var location pathLocation
+	found := false
+	if pp.last >= 0 {
+		from := int(math.Max(0, float64(pp.last-projectionWindow)))
+		location, found = g.locateSegments(c, from, pp.last+projectionWindow+1)
+		found = found && location.offset <= pp.SearchRadius
+	}
+
+	if !found {
+		location, found = g.locateSegments(c, 0, len(g.steps))
+		if !found {
+			return Projection{}, false
+		}
+	}
+	pp.last = location.step
+
+	along := g.routeDistance(location)
+	return Projection{
+		Point:             location.point,
+		StepIndex:         location.step,
+		DistanceAlong:     along,
+		DistanceRemaining: math.Max(0, g.distance-along),
+		DurationRemaining: time.Duration(math.Max(0, g.duration-g.routeDuration(location)) * float64(time.Second)),
+		Offset:            location.lateral,
+	}, true
+}
+
+// Reset forgets the previous result so that the next call searches the whole
+// path.
+func (pp *PathProjector) Reset() {
+	pp.last = -1
+}
+
+// Project snaps c to the closest location on the path. Use a PathProjector for
+// repeated calls on the same path.
+func (p *Path) Project(c Coordinate) (Projection, bool) {
+	return NewPathProjector(p).Project(c)
+}
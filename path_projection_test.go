@@ -0,0 +1,98 @@
+package go_huawei
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStepBoundsAcrossAntimeridian(t *testing.T) {
+	g := newPathGeometry(pacificPath())
+	for i := range g.steps {
+		if span := g.steps[i].bounds.lngSpan(); math.Abs(span-0.02) > 1e-9 {
+			t.Fatalf("step %d bounds = %+v, want 0.02° wide", i, g.steps[i].bounds)
+		}
+	}
+
+	c := Coordinate{Lng: 0}
+	projection := localProjection{origin: c, cosLat: 1}
+	for i := range g.steps {
+		if d := g.steps[i].minDistance(c, projection); d < 170*equatorDegree {
+			t.Errorf("step %d: minDistance from the prime meridian = %.0f m", i, d)
+		}
+	}
+
+	near := Coordinate{Lng: -179.99}
+	projection = localProjection{origin: near, cosLat: 1}
+	if d := g.steps[1].minDistance(near, projection); d != 0 {
+		t.Errorf("minDistance inside the box = %v, want 0", d)
+	}
+	if d, want := g.steps[0].minDistance(near, projection), 0.01*equatorDegree; math.Abs(d-want) > 0.02*want {
+		t.Errorf("minDistance across the antimeridian = %.1f m, want about %.1f m", d, want)
+	}
+}
+
+func TestProjectAcrossAntimeridian(t *testing.T) {
+	projector := NewPathProjector(pacificPath())
+
+	tests := []struct {
+		c         Coordinate
+		step      int
+		along     float64
+		rightSide bool
+	}{
+		{c: Coordinate{Lat: -0.0001, Lng: 179.99}, step: 0, along: 0.01 * equatorDegree, rightSide: true},
+		{c: Coordinate{Lat: 0.0001, Lng: -179.99}, step: 1, along: 0.03 * equatorDegree},
+		{c: Coordinate{Lat: 0.0001, Lng: 179.985}, step: 0, along: 0.005 * equatorDegree},
+	}
+
+	for _, test := range tests {
+		p, ok := projector.Project(test.c)
+		if !ok {
+			t.Fatalf("Project(%v) found nothing", test.c)
+		}
+		if p.StepIndex != test.step || math.Abs(p.DistanceAlong-test.along) > 1 {
+			t.Errorf("Project(%v) = step %d at %.1f m, want step %d at %.1f m", test.c, p.StepIndex, p.DistanceAlong, test.step, test.along)
+		}
+		if math.Abs(math.Abs(p.Offset)-0.0001*equatorDegree) > 0.1 || (p.Offset > 0) != test.rightSide {
+			t.Errorf("Project(%v) offset = %.2f m", test.c, p.Offset)
+		}
+		if math.Abs(p.Point.Lat) > 1e-9 {
+			t.Errorf("Project(%v) point = %v, want it on the equator", test.c, p.Point)
+		}
+	}
+}
+
+func TestProjectOntoStop(t *testing.T) {
+	// An eastbound path with a stop between its two drives, apart from both.
+	path := &Path{Steps: []Step{
+		{Polyline: []Coordinate{{}, {Lng: 0.01}}},
+		{Polyline: []Coordinate{{Lng: 0.02}}},
+		{Polyline: []Coordinate{{Lng: 0.03}, {Lng: 0.04}}},
+	}}
+	d := 0.0001 * equatorDegree
+
+	for _, test := range []struct {
+		name   string
+		path   *Path
+		c      Coordinate
+		offset float64
+	}{
+		{"left of the stop", path, Coordinate{Lat: 0.0001, Lng: 0.02}, -d},
+		{"right of the stop", path, Coordinate{Lat: -0.0001, Lng: 0.02}, d},
+		{"only a stop", &Path{Steps: []Step{{Polyline: []Coordinate{{Lng: 0.02}}}}}, Coordinate{Lat: 0.0001, Lng: 0.02}, d},
+	} {
+		p, ok := test.path.Project(test.c)
+		if !ok || p.StepIndex != len(test.path.Steps)/2 {
+			t.Fatalf("%s: Project(%v) = step %d, %v; want the stop", test.name, &test.c, p.StepIndex, ok)
+		}
+		if math.Abs(p.Offset-test.offset) > 0.01 {
+			t.Errorf("%s: offset = %.2f m, want %.2f m", test.name, p.Offset, test.offset)
+		}
+	}
+}
+
+func TestProjectEmptyPath(t *testing.T) {
+	if _, ok := (&Path{}).Project(Coordinate{}); ok {
+		t.Error("Project on a path without steps succeeded")
+	}
+}
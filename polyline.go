@@ -0,0 +1,207 @@
+package go_huawei
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/twpayne/go-polyline"
+)
+
+// PolylinePrecision is the number of decimal places kept by an encoded
+// polyline.
+type PolylinePrecision int
+
+const (
+	// PolylinePrecision5 is the precision of the Google encoded polyline
+	// format, about one metre.
+	PolylinePrecision5 = PolylinePrecision(5)
+	// PolylinePrecision6 is the precision used by OSRM and Valhalla, about ten
+	// centimetres.
+	PolylinePrecision6 = PolylinePrecision(6)
+)
+
+// PolylineOption is the type of options for polyline encoding and decoding.
+type PolylineOption func(*polylineConfig)
+
+type polylineConfig struct {
+	precision PolylinePrecision
+}
+
+// WithPolylinePrecision sets the precision of encoded polylines. Default is
+// PolylinePrecision5.
+func WithPolylinePrecision(precision PolylinePrecision) PolylineOption {
+	return func(c *polylineConfig) {
+		c.precision = precision
+	}
+}
+
+func newPolylineCodec(options []PolylineOption) polyline.Codec {
+	c := &polylineConfig{precision: PolylinePrecision5}
+	for _, option := range options {
+		option(c)
+	}
+
+	return polyline.Codec{Dim: 2, Scale: math.Pow10(int(c.precision))}
+}
+
+// EncodePolyline returns coords as an encoded polyline.
+func EncodePolyline(coords []Coordinate, options ...PolylineOption) []byte {
+	codec := newPolylineCodec(options)
+
+	flat := make([][]float64, len(coords))
+	for i, c := range coords {
+		flat[i] = []float64{c.Lat, c.Lng}
+	}
+
+	return codec.EncodeCoords(nil, flat)
+}
+
+// DecodePolyline parses an encoded polyline. The precision must match the one
+// it was encoded with.
+func DecodePolyline(encoded []byte, options ...PolylineOption) ([]Coordinate, error) {
+	codec := newPolylineCodec(options)
+
+	flat, rest, err := codec.DecodeCoords(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("map-kit: decode polyline: %w", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("map-kit: decode polyline: %d trailing bytes", len(rest))
+	}
+
+	// The codec sums float deltas, so round away the accumulated error.
+	coords := make([]Coordinate, len(flat))
+	for i, c := range flat {
+		coords[i] = Coordinate{
+			Lat: math.Round(c[0]*codec.Scale) / codec.Scale,
+			Lng: math.Round(c[1]*codec.Scale) / codec.Scale,
+		}
+	}
+
+	return coords, nil
+}
+
+// EncodedPolyline returns the step polyline as an encoded polyline.
+func (s *Step) EncodedPolyline(options ...PolylineOption) []byte {
+	if s == nil {
+		return nil
+	}
+
+	return EncodePolyline(s.Polyline, options...)
+}
+
+// MarshalCompactJSON encodes routes like json.Marshal but with every step
+// "polyline" array replaced by an encoded polyline string, which shrinks the
+// JSON several times. Use DecodePolyline with the same options on the
+// receiving side.
+func MarshalCompactJSON(routes []Route, options ...PolylineOption) ([]byte, error) {
+	data, err := json.Marshal(routes)
+	if err != nil {
+		return nil, err
+	}
+
+	return rewriteSteps(data, func(i, j, k int, step map[string]json.RawMessage) error {
+		encoded, err := json.Marshal(string(routes[i].Paths[j].Steps[k].EncodedPolyline(options...)))
+		if err != nil {
+			return err
+		}
+
+		step["polyline"] = encoded
+		return nil
+	})
+}
+
+// UnmarshalCompactJSON decodes routes written by MarshalCompactJSON.
+func UnmarshalCompactJSON(data []byte, options ...PolylineOption) ([]Route, error) {
+	type stepIndex struct{ route, path, step int }
+	polylines := make(map[stepIndex]string)
+
+	stripped, err := rewriteSteps(data, func(i, j, k int, step map[string]json.RawMessage) error {
+		raw, ok := step["polyline"]
+		if !ok {
+			return nil
+		}
+
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return fmt.Errorf("map-kit: route %d path %d step %d: polyline is not a string", i, j, k)
+		}
+
+		polylines[stepIndex{i, j, k}] = encoded
+		delete(step, "polyline")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []Route
+	if err := json.Unmarshal(stripped, &routes); err != nil {
+		return nil, err
+	}
+
+	for index, encoded := range polylines {
+		step := &routes[index.route].Paths[index.path].Steps[index.step]
+		if step.Polyline, err = DecodePolyline([]byte(encoded), options...); err != nil {
+			return nil, err
+		}
+	}
+
+	return routes, nil
+}
+
+// rewriteSteps calls fn with the JSON object of every step in a JSON array of
+// routes and returns the array with the objects fn modified. Working on raw
+// objects keeps every other key as encoded by the types' own marshalling.
+func rewriteSteps(data []byte, fn func(route, path, step int, object map[string]json.RawMessage) error) ([]byte, error) {
+	var routes []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, err
+	}
+
+	for i, route := range routes {
+		paths, err := unmarshalObjects(route["paths"])
+		if err != nil {
+			return nil, err
+		}
+
+		for j, path := range paths {
+			steps, err := unmarshalObjects(path["steps"])
+			if err != nil {
+				return nil, err
+			}
+
+			for k, step := range steps {
+				if err := fn(i, j, k, step); err != nil {
+					return nil, err
+				}
+			}
+
+			if steps != nil {
+				if path["steps"], err = json.Marshal(steps); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if paths != nil {
+			if route["paths"], err = json.Marshal(paths); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return json.Marshal(routes)
+}
+
+func unmarshalObjects(raw json.RawMessage) ([]map[string]json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var objects []map[string]json.RawMessage
+	err := json.Unmarshal(raw, &objects)
+	return objects, err
+}
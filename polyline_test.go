@@ -0,0 +1,136 @@
+package go_huawei_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/huaweitest"
+)
+
+// The example of the Google encoded polyline algorithm documentation.
+var (
+	googleExample       = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+	googleExampleCoords = []go_huawei.Coordinate{
+		{Lat: 38.5, Lng: -120.2},
+		{Lat: 40.7, Lng: -120.95},
+		{Lat: 43.252, Lng: -126.453},
+	}
+)
+
+func TestEncodePolyline(t *testing.T) {
+	if got := string(go_huawei.EncodePolyline(googleExampleCoords)); got != googleExample {
+		t.Errorf("EncodePolyline = %s, want %s", got, googleExample)
+	}
+
+	got, err := go_huawei.DecodePolyline([]byte(googleExample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, googleExampleCoords) {
+		t.Errorf("DecodePolyline = %v, want %v", got, googleExampleCoords)
+	}
+}
+
+func TestPolylinePrecision(t *testing.T) {
+	coords := []go_huawei.Coordinate{{Lat: 50.450123, Lng: 30.523456}, {Lat: 50.450987, Lng: 30.524321}}
+	precise := go_huawei.WithPolylinePrecision(go_huawei.PolylinePrecision6)
+
+	got, err := go_huawei.DecodePolyline(go_huawei.EncodePolyline(coords, precise), precise)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, coords) {
+		t.Errorf("precision 6 round trip = %v, want %v", got, coords)
+	}
+
+	// Decoding with the wrong precision scales everything tenfold.
+	wrong, err := go_huawei.DecodePolyline(go_huawei.EncodePolyline(coords, precise))
+	if err != nil || math.Abs(wrong[0].Lat-504.50123) > 1e-9 {
+		t.Errorf("precision mismatch decoded as %v, %v", wrong, err)
+	}
+
+	var step *go_huawei.Step
+	if step.EncodedPolyline() != nil {
+		t.Error("nil step has a polyline")
+	}
+}
+
+func TestDecodePolylineErrors(t *testing.T) {
+	for _, encoded := range []string{"_p~iF~ps|U_", "_p~iF"} {
+		if _, err := go_huawei.DecodePolyline([]byte(encoded)); err == nil || !strings.HasPrefix(err.Error(), "map-kit: decode polyline") {
+			t.Errorf("DecodePolyline(%q) = %v", encoded, err)
+		}
+	}
+}
+
+func TestCompactJSON(t *testing.T) {
+	routes := huaweitest.NewRouter().Route(&go_huawei.DirectionsRequest{
+		Origin:       &go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234},
+		Destination:  &go_huawei.Coordinate{Lat: 50.3921, Lng: 30.6421},
+		Alternatives: true,
+	})
+	// Keep what the default precision can represent so that both encodings
+	// carry the same values.
+	for i := range routes {
+		for j := range routes[i].Paths {
+			for k := range routes[i].Paths[j].Steps {
+				polyline := routes[i].Paths[j].Steps[k].Polyline
+				for n := range polyline {
+					polyline[n].Lat = math.Round(polyline[n].Lat*1e5) / 1e5
+					polyline[n].Lng = math.Round(polyline[n].Lng*1e5) / 1e5
+				}
+			}
+		}
+	}
+
+	plain, err := json.Marshal(routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := go_huawei.MarshalCompactJSON(routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(compact) > len(plain)*2/3 {
+		t.Errorf("compact JSON is %d bytes, plain %d", len(compact), len(plain))
+	}
+	if bytes.Contains(compact, []byte(`"polyline":[`)) {
+		t.Error("compact JSON still holds polyline arrays")
+	}
+
+	var want []go_huawei.Route
+	if err := json.Unmarshal(plain, &want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := go_huawei.UnmarshalCompactJSON(compact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("compact round trip differs from the plain one")
+	}
+}
+
+func TestUnmarshalCompactJSONErrors(t *testing.T) {
+	for _, data := range []string{
+		`[{"paths":[{"steps":[{"polyline":[{"lat":1,"lng":2}]}]}]}]`,
+		`[{"paths":[{"steps":[{"polyline":"_p~iF"}]}]}]`,
+		`[{"paths":{}}]`,
+		`{}`,
+	} {
+		if _, err := go_huawei.UnmarshalCompactJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalCompactJSON(%s) succeeded", data)
+		}
+	}
+
+	routes, err := go_huawei.UnmarshalCompactJSON([]byte(`[{"paths":[{"steps":[{"roadName":"A4"}]}]}]`))
+	if err != nil || routes[0].Paths[0].Steps[0].RoadName != "A4" || routes[0].Paths[0].Steps[0].Polyline != nil {
+		t.Errorf("step without polyline = %+v, %v", routes, err)
+	}
+}
@@ -0,0 +1,338 @@
+// Package projection converts coordinates to Web Mercator pixels and map tiles
+// in the XYZ scheme used by OpenStreetMap and most tile servers, and to Bing
+// Maps quadkeys.
+package projection
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/internal/mercator"
+)
+
+const (
+	// TileSize is the width and height of a tile, in pixels.
+	TileSize = mercator.TileSize
+	// MaxLatitude is the latitude where Web Mercator is cut off, making the
+	// world square.
+	MaxLatitude = mercator.MaxLatitude
+	// MaxZoom is the deepest zoom level supported.
+	MaxZoom = 30
+)
+
+// ErrInvalidQuadKey is returned for quadkeys with digits other than 0-3 or
+// longer than MaxZoom.
+var ErrInvalidQuadKey = errors.New("projection: invalid quadkey")
+
+// ErrInvalidZoom is returned for tile zoom levels outside 0 to MaxZoom.
+var ErrInvalidZoom = errors.New("projection: invalid zoom")
+
+// Point is a position in world pixels: x grows east from the antimeridian and
+// y grows south from MaxLatitude.
+type Point struct {
+	X, Y float64
+}
+
+// WorldSize returns the width and height of the world at the zoom, in pixels.
+func WorldSize(zoom float64) float64 {
+	return mercator.WorldSize(zoom)
+}
+
+// Project returns the world pixel position of c at the zoom. Latitudes are
+// clamped to ±MaxLatitude.
+func Project(c go_huawei.Coordinate, zoom float64) Point {
+	size := WorldSize(zoom)
+	return Point{
+		X: (c.Lng + 180) / 360 * size,
+		Y: (1 - mercator.Y(c.Lat)/math.Pi) / 2 * size,
+	}
+}
+
+// Unproject returns the coordinate at a world pixel position at the zoom.
+func Unproject(p Point, zoom float64) go_huawei.Coordinate {
+	size := WorldSize(zoom)
+	y := math.Pi * (1 - 2*p.Y/size)
+	return go_huawei.Coordinate{
+		Lng: p.X/size*360 - 180,
+		Lat: math.Atan(math.Sinh(y)) * 180 / math.Pi,
+	}
+}
+
+// MercatorY returns the Web Mercator ordinate of a latitude, in radians of the
+// equator: 0 at the equator and ±π at ±MaxLatitude.
+func MercatorY(lat float64) float64 {
+	return mercator.Y(lat)
+}
+
+// Tile is a map tile in the XYZ scheme: X grows east and Y grows south from
+// the north-west corner of the world.
+type Tile struct {
+	X, Y, Z int
+}
+
+// TileAt returns the tile containing c at zoom z.
+func TileAt(c go_huawei.Coordinate, z int) (Tile, error) {
+	if err := checkZoom(z); err != nil {
+		return Tile{}, err
+	}
+
+	return tileAtPoint(Project(c, float64(z)), z), nil
+}
+
+func checkZoom(z int) error {
+	if z < 0 || z > MaxZoom {
+		return fmt.Errorf("%w %d", ErrInvalidZoom, z)
+	}
+
+	return nil
+}
+
+func tileAtPoint(p Point, z int) Tile {
+	count := 1 << uint(z)
+	x := int(math.Floor(p.X / TileSize))
+	y := int(math.Floor(p.Y / TileSize))
+
+	return Tile{X: wrap(x, count), Y: clamp(y, 0, count-1), Z: z}
+}
+
+// String returns the tile as "z/x/y".
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// Valid reports whether the tile exists at its zoom.
+func (t Tile) Valid() bool {
+	count := 1 << uint(t.Z)
+	return t.Z >= 0 && t.Z <= MaxZoom && t.X >= 0 && t.X < count && t.Y >= 0 && t.Y < count
+}
+
+// Bounds returns the area covered by the tile.
+func (t Tile) Bounds() go_huawei.CoordinateBounds {
+	z := float64(t.Z)
+	return go_huawei.CoordinateBounds{
+		Southwest: Unproject(Point{X: float64(t.X) * TileSize, Y: float64(t.Y+1) * TileSize}, z),
+		Northeast: Unproject(Point{X: float64(t.X+1) * TileSize, Y: float64(t.Y) * TileSize}, z),
+	}
+}
+
+// Origin returns the world pixel position of the north-west corner of the
+// tile.
+func (t Tile) Origin() Point {
+	return Point{X: float64(t.X) * TileSize, Y: float64(t.Y) * TileSize}
+}
+
+// Parent returns the tile one zoom level up containing t. The parent of a zoom
+// 0 tile is itself.
+func (t Tile) Parent() Tile {
+	if t.Z == 0 {
+		return t
+	}
+
+	return Tile{X: t.X / 2, Y: t.Y / 2, Z: t.Z - 1}
+}
+
+// Children returns the four tiles one zoom level down covering t, in quadkey
+// order: north-west, north-east, south-west, south-east.
+func (t Tile) Children() [4]Tile {
+	x, y, z := t.X*2, t.Y*2, t.Z+1
+	return [4]Tile{{x, y, z}, {x + 1, y, z}, {x, y + 1, z}, {x + 1, y + 1, z}}
+}
+
+// QuadKey returns the Bing Maps quadkey of the tile. Zoom 0 has an empty key.
+func (t Tile) QuadKey() string {
+	var key strings.Builder
+	for i := t.Z; i > 0; i-- {
+		digit := byte('0')
+		mask := 1 << uint(i-1)
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		key.WriteByte(digit)
+	}
+
+	return key.String()
+}
+
+// TileFromQuadKey parses a Bing Maps quadkey.
+func TileFromQuadKey(key string) (Tile, error) {
+	if len(key) > MaxZoom {
+		return Tile{}, ErrInvalidQuadKey
+	}
+
+	t := Tile{Z: len(key)}
+	for i := 0; i < len(key); i++ {
+		mask := 1 << uint(len(key)-i-1)
+		switch key[i] {
+		case '0':
+		case '1':
+			t.X |= mask
+		case '2':
+			t.Y |= mask
+		case '3':
+			t.X |= mask
+			t.Y |= mask
+		default:
+			return Tile{}, ErrInvalidQuadKey
+		}
+	}
+
+	return t, nil
+}
+
+// BoundsTiles returns the tiles covering b at zoom z, row by row from the
+// north-west. Bounds crossing the antimeridian wrap around.
+func BoundsTiles(b go_huawei.CoordinateBounds, z int) ([]Tile, error) {
+	if err := checkZoom(z); err != nil {
+		return nil, err
+	}
+	if b.IsEmpty() {
+		return nil, nil
+	}
+
+	count := 1 << uint(z)
+	nw := tileAtPoint(Project(go_huawei.Coordinate{Lat: b.Northeast.Lat, Lng: b.Southwest.Lng}, float64(z)), z)
+	se := tileAtPoint(Project(go_huawei.Coordinate{Lat: b.Southwest.Lat, Lng: b.Northeast.Lng}, float64(z)), z)
+	// The east edge of the world belongs to the last column, not to the
+	// first one again.
+	if b.Northeast.Lng == 180 {
+		se.X = count - 1
+	}
+
+	columns := se.X - nw.X + 1
+	if b.CrossesAntimeridian() || columns <= 0 {
+		columns += count
+	}
+	columns = clamp(columns, 1, count)
+
+	var tiles []Tile
+	for y := nw.Y; y <= se.Y; y++ {
+		for i := 0; i < columns; i++ {
+			tiles = append(tiles, Tile{X: wrap(nw.X+i, count), Y: y, Z: z})
+		}
+	}
+
+	return tiles, nil
+}
+
+// PolylineTiles returns the tiles a polyline passes through at zoom z, in the
+// order it enters them. Segments take the shorter way around the world, so
+// lines crossing the antimeridian stay continuous.
+func PolylineTiles(coords []go_huawei.Coordinate, z int) ([]Tile, error) {
+	if err := checkZoom(z); err != nil {
+		return nil, err
+	}
+	if len(coords) == 0 {
+		return nil, nil
+	}
+
+	size := WorldSize(float64(z))
+	seen := make(map[Tile]bool)
+	var tiles []Tile
+	add := func(t Tile) {
+		if !seen[t] {
+			seen[t] = true
+			tiles = append(tiles, t)
+		}
+	}
+
+	previous := Project(coords[0], float64(z))
+	add(tileAtPoint(previous, z))
+
+	for _, c := range coords[1:] {
+		p := Project(c, float64(z))
+		if p.X-previous.X > size/2 {
+			p.X -= size
+		} else if previous.X-p.X > size/2 {
+			p.X += size
+		}
+
+		walkSegment(previous, p, func(x, y int) {
+			add(tileAtPoint(Point{X: (float64(x) + 0.5) * TileSize, Y: (float64(y) + 0.5) * TileSize}, z))
+		})
+		previous = p
+	}
+
+	return tiles, nil
+}
+
+// PathTiles returns the tiles the overview of the path passes through at zoom
+// z. See PolylineTiles.
+func PathTiles(p *go_huawei.Path, z int) ([]Tile, error) {
+	return PolylineTiles(go_huawei.RemoveDuplicates(p.Overview()), z)
+}
+
+// walkSegment calls visit with every tile column and row the segment a-b
+// crosses, from a to b, using the grid traversal of Amanatides and Woo.
+func walkSegment(a, b Point, visit func(x, y int)) {
+	x, y := int(math.Floor(a.X/TileSize)), int(math.Floor(a.Y/TileSize))
+	endX, endY := int(math.Floor(b.X/TileSize)), int(math.Floor(b.Y/TileSize))
+	visit(x, y)
+
+	dx, dy := b.X-a.X, b.Y-a.Y
+	stepX, stepY := 1, 1
+	if dx < 0 {
+		stepX = -1
+	}
+	if dy < 0 {
+		stepY = -1
+	}
+
+	// tMaxX and tMaxY are the fractions of the segment at which it crosses the
+	// next column and row boundaries; tDeltaX and tDeltaY the fractions
+	// between boundaries.
+	tMaxX, tDeltaX := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		next := float64(x) * TileSize
+		if stepX > 0 {
+			next += TileSize
+		}
+		tMaxX, tDeltaX = (next-a.X)/dx, TileSize/math.Abs(dx)
+	}
+
+	tMaxY, tDeltaY := math.Inf(1), math.Inf(1)
+	if dy != 0 {
+		next := float64(y) * TileSize
+		if stepY > 0 {
+			next += TileSize
+		}
+		tMaxY, tDeltaY = (next-a.Y)/dy, TileSize/math.Abs(dy)
+	}
+
+	for x != endX || y != endY {
+		if tMaxX < tMaxY {
+			if tMaxX > 1 {
+				break
+			}
+			x += stepX
+			tMaxX += tDeltaX
+		} else {
+			if tMaxY > 1 {
+				break
+			}
+			y += stepY
+			tMaxY += tDeltaY
+		}
+		visit(x, y)
+	}
+}
+
+func wrap(x, count int) int {
+	return ((x % count) + count) % count
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+
+	return v
+}
@@ -0,0 +1,199 @@
+package projection
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		c    go_huawei.Coordinate
+		zoom float64
+		want Point
+	}{
+		{go_huawei.Coordinate{}, 0, Point{128, 128}},
+		{go_huawei.Coordinate{Lat: MaxLatitude, Lng: -180}, 1, Point{0, 0}},
+		{go_huawei.Coordinate{Lat: -90, Lng: 180}, 1, Point{512, 512}},
+		{go_huawei.Coordinate{Lat: 0, Lng: 90}, 2, Point{768, 512}},
+	}
+
+	for _, test := range tests {
+		got := Project(test.c, test.zoom)
+		if math.Abs(got.X-test.want.X) > 1e-9 || math.Abs(got.Y-test.want.Y) > 1e-9 {
+			t.Errorf("Project(%v, %v) = %v, want %v", test.c, test.zoom, got, test.want)
+		}
+	}
+
+	for _, c := range []go_huawei.Coordinate{{Lat: 50.4501, Lng: 30.5234}, {Lat: -33.8688, Lng: 151.2093}, {Lat: 84.9, Lng: -179.9}} {
+		back := Unproject(Project(c, 15.5), 15.5)
+		if math.Abs(back.Lat-c.Lat) > 1e-9 || math.Abs(back.Lng-c.Lng) > 1e-9 {
+			t.Errorf("Unproject(Project(%v)) = %v", c, back)
+		}
+	}
+}
+
+func TestTileAt(t *testing.T) {
+	seattle := go_huawei.Coordinate{Lat: 47.6062, Lng: -122.3321}
+	tile, err := TileAt(seattle, 12)
+	if err != nil || tile != (Tile{X: 656, Y: 1430, Z: 12}) {
+		t.Errorf("TileAt(Seattle, 12) = %v, %v", tile, err)
+	}
+	if got, _ := TileAt(go_huawei.Coordinate{Lat: -89, Lng: 180}, 2); got != (Tile{X: 0, Y: 3, Z: 2}) {
+		t.Errorf("TileAt of the south-east corner = %v, want it wrapped and clamped", got)
+	}
+	if got, err := TileAt(seattle, MaxZoom); err != nil || !got.Valid() {
+		t.Errorf("TileAt(Seattle, MaxZoom) = %v, %v", got, err)
+	}
+
+	b := tile.Bounds()
+	if !b.Contains(seattle) {
+		t.Errorf("%v bounds %+v miss Seattle", tile, b)
+	}
+	if o := tile.Origin(); o != (Point{656 * TileSize, 1430 * TileSize}) {
+		t.Errorf("Origin = %v", o)
+	}
+	if s := tile.String(); s != "12/656/1430" {
+		t.Errorf("String = %s", s)
+	}
+}
+
+func TestTileHierarchy(t *testing.T) {
+	tile := Tile{X: 3, Y: 5, Z: 3}
+	if !tile.Valid() || (Tile{X: 8, Y: 0, Z: 3}).Valid() || (Tile{Z: -1}).Valid() {
+		t.Error("Valid disagrees with the tile grid")
+	}
+	if p := tile.Parent(); p != (Tile{X: 1, Y: 2, Z: 2}) {
+		t.Errorf("Parent = %v", p)
+	}
+	if p := (Tile{}).Parent(); p != (Tile{}) {
+		t.Errorf("Parent of the root = %v", p)
+	}
+
+	for i, child := range tile.Children() {
+		if child.Parent() != tile {
+			t.Errorf("child %d %v has parent %v", i, child, child.Parent())
+		}
+		// Children come in quadkey order.
+		if key := child.QuadKey(); key != tile.QuadKey()+string(rune('0'+i)) {
+			t.Errorf("child %d quadkey = %s", i, key)
+		}
+	}
+}
+
+func TestQuadKey(t *testing.T) {
+	tests := map[Tile]string{
+		{Z: 0}:                      "",
+		{X: 3, Y: 5, Z: 3}:          "213",
+		{X: 1, Y: 1, Z: 1}:          "3",
+		{X: 35210, Y: 21493, Z: 16}: "1202102332221212",
+	}
+
+	for tile, key := range tests {
+		if got := tile.QuadKey(); got != key {
+			t.Errorf("%v.QuadKey() = %q, want %q", tile, got, key)
+		}
+		if got, err := TileFromQuadKey(key); err != nil || got != tile {
+			t.Errorf("TileFromQuadKey(%q) = %v, %v", key, got, err)
+		}
+	}
+
+	for _, key := range []string{"124", "0123012301230123012301230123012"} {
+		if _, err := TileFromQuadKey(key); err != ErrInvalidQuadKey {
+			t.Errorf("TileFromQuadKey(%q): err = %v", key, err)
+		}
+	}
+}
+
+func TestBoundsTiles(t *testing.T) {
+	tests := []struct {
+		name string
+		b    go_huawei.CoordinateBounds
+		z    int
+		want []Tile
+	}{
+		{
+			name: "world",
+			b:    go_huawei.CoordinateBounds{Southwest: go_huawei.Coordinate{Lat: -80, Lng: -180}, Northeast: go_huawei.Coordinate{Lat: 80, Lng: 180}},
+			z:    1,
+			want: []Tile{{0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1}},
+		},
+		{
+			name: "north-east quarter",
+			b:    go_huawei.NewCoordinateBounds([]go_huawei.Coordinate{{Lat: 10, Lng: 10}, {Lat: 70, Lng: 100}}),
+			z:    2,
+			want: []Tile{{2, 0, 2}, {3, 0, 2}, {2, 1, 2}, {3, 1, 2}},
+		},
+		{
+			name: "antimeridian",
+			b:    go_huawei.NewCoordinateBounds([]go_huawei.Coordinate{{Lat: -19, Lng: 177}, {Lat: -16, Lng: -179}}),
+			z:    3,
+			want: []Tile{{7, 4, 3}, {0, 4, 3}},
+		},
+		{
+			name: "empty",
+			b:    go_huawei.CoordinateBounds{},
+			z:    5,
+		},
+	}
+
+	for _, test := range tests {
+		if got, err := BoundsTiles(test.b, test.z); err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: BoundsTiles = %v, %v; want %v", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestPolylineTiles(t *testing.T) {
+	// A diagonal across the zoom 2 grid visits every tile it crosses, in
+	// order.
+	diagonal := []go_huawei.Coordinate{{Lat: 70, Lng: -100}, {Lat: -10, Lng: 50}}
+	want := []Tile{{0, 0, 2}, {1, 0, 2}, {1, 1, 2}, {2, 1, 2}, {2, 2, 2}}
+	if got, _ := PolylineTiles(diagonal, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("diagonal: PolylineTiles = %v, want %v", got, want)
+	}
+
+	// Crossing the antimeridian goes the short way, not around the world.
+	pacific := []go_huawei.Coordinate{{Lat: 1, Lng: 170}, {Lat: 1, Lng: -170}, {Lat: 1, Lng: 170}}
+	want = []Tile{{3, 1, 2}, {0, 1, 2}}
+	if got, _ := PolylineTiles(pacific, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("pacific: PolylineTiles = %v, want %v", got, want)
+	}
+
+	if got, err := PolylineTiles(nil, 4); got != nil || err != nil {
+		t.Errorf("PolylineTiles(nil) = %v, %v", got, err)
+	}
+}
+
+func TestInvalidZoom(t *testing.T) {
+	c := go_huawei.Coordinate{Lat: 50.4501, Lng: 30.5234}
+	polyline := []go_huawei.Coordinate{c, {Lat: 50.5, Lng: 30.6}}
+	b := go_huawei.NewCoordinateBounds(polyline)
+	path := &go_huawei.Path{Steps: []go_huawei.Step{{Polyline: polyline}}}
+
+	for _, z := range []int{-1, MaxZoom + 1, 63, 64} {
+		if _, err := TileAt(c, z); !errors.Is(err, ErrInvalidZoom) {
+			t.Errorf("TileAt at zoom %d: err = %v", z, err)
+		}
+		if _, err := BoundsTiles(b, z); !errors.Is(err, ErrInvalidZoom) {
+			t.Errorf("BoundsTiles at zoom %d: err = %v", z, err)
+		}
+		if _, err := PolylineTiles(polyline, z); !errors.Is(err, ErrInvalidZoom) {
+			t.Errorf("PolylineTiles at zoom %d: err = %v", z, err)
+		}
+		if _, err := PathTiles(path, z); !errors.Is(err, ErrInvalidZoom) {
+			t.Errorf("PathTiles at zoom %d: err = %v", z, err)
+		}
+	}
+
+	// Empty input is still checked.
+	if _, err := BoundsTiles(go_huawei.CoordinateBounds{}, -1); !errors.Is(err, ErrInvalidZoom) {
+		t.Errorf("BoundsTiles of empty bounds at zoom -1: err = %v", err)
+	}
+	if _, err := PolylineTiles(nil, 64); err == nil || err.Error() != "projection: invalid zoom 64" {
+		t.Errorf("PolylineTiles(nil) at zoom 64: err = %v", err)
+	}
+}
@@ -0,0 +1,426 @@
+// Package render draws routes onto images without network access: paths,
+// markers and bounds are projected with Web Mercator onto an optional tile
+// background and can be encoded to PNG.
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/internal/mercator"
+	"github.com/stremovskyy/go-huawei/projection"
+)
+
+const (
+	defaultPadding = 20
+	maxZoom        = 20
+)
+
+// Style controls how map content is drawn.
+type Style struct {
+	// Background fills the image where there are no tiles.
+	Background color.Color
+	// Colors are the line colours of successive paths, cycling when there are
+	// more paths than colours. The first path is drawn on top.
+	Colors []color.Color
+	// LineWidth is the width of path lines, in pixels.
+	LineWidth float64
+	// Casing is drawn under path lines, CasingWidth pixels wider; nil disables
+	// it.
+	Casing      color.Color
+	CasingWidth float64
+
+	// StartColor and EndColor fill the pins at the start and end of the first
+	// path; nil disables them.
+	StartColor color.Color
+	EndColor   color.Color
+	// PinRadius is the radius of start and end pins, in pixels.
+	PinRadius float64
+
+	// BoundsColor and BoundsWidth draw the outline of added bounds.
+	BoundsColor color.Color
+	BoundsWidth float64
+}
+
+// DefaultStyle returns the style used by NewMap.
+func DefaultStyle() Style {
+	return Style{
+		Background: color.NRGBA{R: 0xf2, G: 0xef, B: 0xe9, A: 0xff},
+		Colors: []color.Color{
+			color.NRGBA{R: 0x1a, G: 0x73, B: 0xe8, A: 0xff},
+			color.NRGBA{R: 0x75, G: 0x75, B: 0x75, A: 0xcc},
+			color.NRGBA{R: 0xe3, G: 0x74, B: 0x00, A: 0xcc},
+		},
+		LineWidth:   5,
+		Casing:      color.White,
+		CasingWidth: 3,
+		StartColor:  color.NRGBA{R: 0x1e, G: 0x8e, B: 0x3e, A: 0xff},
+		EndColor:    color.NRGBA{R: 0xd9, G: 0x30, B: 0x25, A: 0xff},
+		PinRadius:   7,
+		BoundsColor: color.NRGBA{R: 0xd9, G: 0x30, B: 0x25, A: 0x99},
+		BoundsWidth: 2,
+	}
+}
+
+// Marker is a dot drawn at a location.
+type Marker struct {
+	Location go_huawei.Coordinate
+	Color    color.Color
+	// Radius in pixels. Style.PinRadius is used when zero.
+	Radius float64
+}
+
+// Option is the type of constructor options for NewMap(...).
+type Option func(*Map)
+
+// WithStyle sets the drawing style. Default is DefaultStyle().
+func WithStyle(style Style) Option {
+	return func(m *Map) {
+		m.style = style
+	}
+}
+
+// WithTileProvider draws background tiles from the provider. The zoom is then
+// rounded down to a whole level.
+func WithTileProvider(provider TileProvider) Option {
+	return func(m *Map) {
+		m.tiles = provider
+	}
+}
+
+// WithPadding sets the margin, in pixels, kept between the content and the
+// image edges when the view is fitted to the content. Default is 20. Render
+// fails if the padding leaves no room for the content.
+func WithPadding(padding int) Option {
+	return func(m *Map) {
+		m.padding = padding
+	}
+}
+
+// WithView fixes the centre and zoom instead of fitting the content.
+func WithView(center go_huawei.Coordinate, zoom float64) Option {
+	return func(m *Map) {
+		m.center = &center
+		m.zoom = zoom
+	}
+}
+
+// Map is a static map being composed. Add content, then call Render or
+// EncodePNG.
+type Map struct {
+	width, height int
+	style         Style
+	tiles         TileProvider
+	padding       int
+
+	center *go_huawei.Coordinate
+	zoom   float64
+
+	paths   []*go_huawei.Path
+	markers []Marker
+	bounds  []go_huawei.CoordinateBounds
+}
+
+// NewMap returns an empty map of the given size in pixels.
+func NewMap(width, height int, options ...Option) *Map {
+	m := &Map{
+		width:   width,
+		height:  height,
+		style:   DefaultStyle(),
+		padding: defaultPadding,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// AddPath adds a path. Paths take the colours of Style.Colors in the order they
+// are added.
+func (m *Map) AddPath(path *go_huawei.Path) *Map {
+	if path != nil {
+		m.paths = append(m.paths, path)
+	}
+
+	return m
+}
+
+// AddRoutes adds every path of the routes.
+func (m *Map) AddRoutes(routes ...go_huawei.Route) *Map {
+	for i := range routes {
+		for j := range routes[i].Paths {
+			m.AddPath(&routes[i].Paths[j])
+		}
+	}
+
+	return m
+}
+
+// AddMarker adds a marker.
+func (m *Map) AddMarker(marker Marker) *Map {
+	m.markers = append(m.markers, marker)
+	return m
+}
+
+// AddBounds adds the outline of bounds.
+func (m *Map) AddBounds(bounds go_huawei.CoordinateBounds) *Map {
+	m.bounds = append(m.bounds, bounds)
+	return m
+}
+
+// Render draws the map.
+func (m *Map) Render(ctx context.Context) (*image.RGBA, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, m.width, m.height))
+	if m.style.Background != nil {
+		draw.Draw(img, img.Bounds(), image.NewUniform(m.style.Background), image.Point{}, draw.Src)
+	}
+
+	v := m.viewport()
+
+	if m.tiles != nil {
+		if err := m.drawTiles(ctx, img, v); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, b := range m.bounds {
+		m.drawBounds(img, v, b)
+	}
+
+	// Draw the first path last so that it stays on top of alternatives.
+	for i := len(m.paths) - 1; i >= 0; i-- {
+		points := v.project(go_huawei.RemoveDuplicates(m.paths[i].Overview()))
+		if len(points) == 0 {
+			continue
+		}
+
+		if m.style.Casing != nil && m.style.CasingWidth > 0 {
+			strokePolyline(img, points, m.style.LineWidth+m.style.CasingWidth, m.style.Casing)
+		}
+		strokePolyline(img, points, m.style.LineWidth, m.pathColor(i))
+	}
+
+	if len(m.paths) > 0 {
+		first := m.paths[0]
+		m.drawPin(img, v.point(first.StartLocation), m.style.PinRadius, m.style.StartColor)
+		m.drawPin(img, v.point(first.EndLocation), m.style.PinRadius, m.style.EndColor)
+	}
+
+	for _, marker := range m.markers {
+		radius := marker.Radius
+		if radius == 0 {
+			radius = m.style.PinRadius
+		}
+		m.drawPin(img, v.point(marker.Location), radius, marker.Color)
+	}
+
+	return img, nil
+}
+
+// EncodePNG renders the map and writes it to w as PNG.
+func (m *Map) EncodePNG(ctx context.Context, w io.Writer) error {
+	img, err := m.Render(ctx)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+func (m *Map) pathColor(i int) color.Color {
+	if len(m.style.Colors) == 0 {
+		return DefaultStyle().Colors[0]
+	}
+
+	return m.style.Colors[i%len(m.style.Colors)]
+}
+
+func (m *Map) drawPin(img draw.Image, p point, radius float64, col color.Color) {
+	if col == nil || radius <= 0 {
+		return
+	}
+
+	fillCircle(img, p, radius+2, color.White)
+	fillCircle(img, p, radius, col)
+}
+
+func (m *Map) drawBounds(img draw.Image, v viewport, b go_huawei.CoordinateBounds) {
+	if m.style.BoundsColor == nil || b.IsEmpty() {
+		return
+	}
+
+	sw, ne := v.point(b.Southwest), v.point(b.Northeast)
+	if ne.x < sw.x {
+		// Crossing the antimeridian: the east edge lies one world further east.
+		ne.x += v.worldSize
+	}
+
+	strokePolyline(img, []point{sw, {ne.x, sw.y}, ne, {sw.x, ne.y}, sw}, m.style.BoundsWidth, m.style.BoundsColor)
+}
+
+func (m *Map) drawTiles(ctx context.Context, img draw.Image, v viewport) error {
+	z := int(v.zoom)
+	count := 1 << uint(z)
+
+	minX := int(math.Floor(v.left / TileSize))
+	maxX := int(math.Floor((v.left + float64(m.width)) / TileSize))
+	minY := int(math.Max(0, math.Floor(v.top/TileSize)))
+	maxY := int(math.Min(float64(count-1), math.Floor((v.top+float64(m.height))/TileSize)))
+
+	for ty := minY; ty <= maxY; ty++ {
+		for tx := minX; tx <= maxX; tx++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// Wrap horizontally so that views across the antimeridian repeat
+			// the world.
+			t := projection.Tile{X: ((tx % count) + count) % count, Y: ty, Z: z}
+			tile, err := m.tiles.Tile(ctx, t.Z, t.X, t.Y)
+			if errors.Is(err, ErrTileNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("render: tile %s: %w", t, err)
+			}
+
+			origin := projection.Tile{X: tx, Y: ty, Z: z}.Origin()
+			at := image.Pt(int(math.Round(origin.X-v.left)), int(math.Round(origin.Y-v.top)))
+			// Clip to the tile area; some images, such as image.Uniform, are unbounded.
+			draw.Draw(img, image.Rectangle{Min: at, Max: at.Add(image.Pt(TileSize, TileSize))}, tile, tile.Bounds().Min, draw.Over)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the image size and, when the view is fitted to the content,
+// that the padding leaves room for it.
+func (m *Map) validate() error {
+	if m.width <= 0 || m.height <= 0 {
+		return fmt.Errorf("render: invalid size %dx%d", m.width, m.height)
+	}
+	if m.center == nil && (2*m.padding >= m.width || 2*m.padding >= m.height) {
+		return fmt.Errorf("render: padding %d leaves no room in %dx%d", m.padding, m.width, m.height)
+	}
+
+	return nil
+}
+
+// viewport maps coordinates to image pixels at a zoom level.
+type viewport struct {
+	zoom      float64
+	worldSize float64
+	// centerX is the world x of the image centre, used to pick the copy of the
+	// world nearest to it.
+	centerX   float64
+	left, top float64
+}
+
+func (m *Map) viewport() viewport {
+	center, zoom := m.fit()
+	if m.center != nil {
+		center, zoom = *m.center, m.zoom
+	}
+	if m.tiles != nil {
+		zoom = math.Floor(zoom)
+	}
+
+	v := viewport{zoom: zoom, worldSize: projection.WorldSize(zoom)}
+	c := projection.Project(center, zoom)
+	v.centerX = c.X
+	v.left = c.X - float64(m.width)/2
+	v.top = c.Y - float64(m.height)/2
+
+	return v
+}
+
+// fit returns the centre and zoom showing all content within the padding.
+func (m *Map) fit() (go_huawei.Coordinate, float64) {
+	var coords []go_huawei.Coordinate
+	for _, p := range m.paths {
+		coords = append(coords, p.StartLocation, p.EndLocation)
+		coords = append(coords, p.Overview()...)
+	}
+	for _, marker := range m.markers {
+		coords = append(coords, marker.Location)
+	}
+
+	content := go_huawei.NewCoordinateBounds(coords)
+	for _, b := range m.bounds {
+		if content.IsEmpty() {
+			content = b
+			continue
+		}
+		content = content.Union(b)
+	}
+
+	if content.IsEmpty() {
+		return go_huawei.Coordinate{}, 0
+	}
+
+	width := float64(m.width - 2*m.padding)
+	height := float64(m.height - 2*m.padding)
+
+	_, lngSpan := content.Span()
+	zoom := mercator.FitZoom(content.Southwest.Lat, content.Northeast.Lat, lngSpan, width, height, maxZoom)
+	ne, sw := projection.Project(content.Northeast, 0), projection.Project(content.Southwest, 0)
+
+	// Centre on the middle of the projected bounds rather than the middle
+	// latitude, which Mercator stretches.
+	center := content.Center()
+	center.Lat = projection.Unproject(projection.Point{Y: (ne.Y + sw.Y) / 2}, 0).Lat
+
+	return center, math.Max(0, zoom)
+}
+
+// point returns the image position of c, using the copy of the world closest to
+// the centre of the image.
+func (v viewport) point(c go_huawei.Coordinate) point {
+	world := projection.Project(c, v.zoom)
+	x, y := world.X, world.Y
+	for x-v.centerX > v.worldSize/2 {
+		x -= v.worldSize
+	}
+	for v.centerX-x > v.worldSize/2 {
+		x += v.worldSize
+	}
+
+	return point{x: x - v.left, y: y - v.top}
+}
+
+// project returns the image positions of a polyline. Consecutive vertices are
+// kept on the same copy of the world so that lines crossing the antimeridian
+// stay continuous.
+func (v viewport) project(coords []go_huawei.Coordinate) []point {
+	points := make([]point, len(coords))
+	for i, c := range coords {
+		points[i] = v.point(c)
+		if i == 0 {
+			continue
+		}
+
+		for points[i].x-points[i-1].x > v.worldSize/2 {
+			points[i].x -= v.worldSize
+		}
+		for points[i-1].x-points[i].x > v.worldSize/2 {
+			points[i].x += v.worldSize
+		}
+	}
+
+	return points
+}
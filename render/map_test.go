@@ -0,0 +1,232 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+// equatorPath runs east along the equator from 1°W to 1°E.
+func equatorPath(offset float64) *go_huawei.Path {
+	start := go_huawei.Coordinate{Lat: offset, Lng: -1}
+	end := go_huawei.Coordinate{Lat: offset, Lng: 1}
+
+	return &go_huawei.Path{
+		StartLocation: start,
+		EndLocation:   end,
+		Steps:         []go_huawei.Step{{Polyline: []go_huawei.Coordinate{start, end}}},
+	}
+}
+
+func rgba(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+func pixelAt(img *image.RGBA, v viewport, c go_huawei.Coordinate) color.RGBA {
+	p := v.point(c)
+	return img.RGBAAt(int(p.x), int(p.y))
+}
+
+func TestRenderPaths(t *testing.T) {
+	style := DefaultStyle()
+	m := NewMap(400, 200, WithView(go_huawei.Coordinate{}, 7)).
+		AddPath(equatorPath(0)).
+		AddPath(equatorPath(0.5)).
+		AddMarker(Marker{Location: go_huawei.Coordinate{Lat: -0.5}, Color: color.Black, Radius: 3})
+
+	img, err := m.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := m.viewport()
+
+	if got, want := img.RGBAAt(0, 0), rgba(style.Background); got != want {
+		t.Errorf("corner = %v, want the background %v", got, want)
+	}
+	if got, want := pixelAt(img, v, go_huawei.Coordinate{Lng: 0.5}), rgba(style.Colors[0]); got != want {
+		t.Errorf("first path = %v, want %v", got, want)
+	}
+	if got, want := pixelAt(img, v, go_huawei.Coordinate{Lng: -1}), rgba(style.StartColor); got != want {
+		t.Errorf("start pin = %v, want %v", got, want)
+	}
+	if got, want := pixelAt(img, v, go_huawei.Coordinate{Lng: 1}), rgba(style.EndColor); got != want {
+		t.Errorf("end pin = %v, want %v", got, want)
+	}
+	if got := pixelAt(img, v, go_huawei.Coordinate{Lat: -0.5}); got != rgba(color.Black) {
+		t.Errorf("marker = %v, want black", got)
+	}
+
+	// The second colour is translucent NRGBA and blends with the casing.
+	alternative := style.Colors[1].(color.NRGBA)
+	casing := rgba(style.Casing)
+	got := pixelAt(img, v, go_huawei.Coordinate{Lat: 0.5})
+	blend := func(src, dst uint8) uint8 {
+		return uint8((int(src)*int(alternative.A) + int(dst)*(0xff-int(alternative.A)) + 0x7f) / 0xff)
+	}
+	want := color.RGBA{R: blend(alternative.R, casing.R), G: blend(alternative.G, casing.G), B: blend(alternative.B, casing.B), A: 0xff}
+	if diff(got.R, want.R) > 1 || diff(got.G, want.G) > 1 || diff(got.B, want.B) > 1 || got.A != 0xff {
+		t.Errorf("alternative = %v, want %v", got, want)
+	}
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+
+	return int(b - a)
+}
+
+func TestRenderFitsContent(t *testing.T) {
+	m := NewMap(300, 300).AddPath(equatorPath(0))
+	img, err := m.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := m.viewport()
+	for _, c := range []go_huawei.Coordinate{{Lng: -1}, {Lng: 1}} {
+		p := v.point(c)
+		if p.x < defaultPadding-1 || p.x > float64(img.Bounds().Dx()-defaultPadding+1) {
+			t.Errorf("%v drawn at x %.1f, outside the padding", c, p.x)
+		}
+	}
+	if v.zoom < 6 || v.zoom > 8 {
+		t.Errorf("zoom = %v, want about 7 for two degrees in 260 pixels", v.zoom)
+	}
+}
+
+func TestRenderTiles(t *testing.T) {
+	colors := []color.Color{color.NRGBA{R: 0xff, A: 0xff}, color.NRGBA{B: 0xff, A: 0xff}}
+
+	requested := 0
+	provider := TileProviderFunc(func(_ context.Context, z, x, y int) (image.Image, error) {
+		requested++
+		if z != 1 || x < 0 || x > 1 || y < 0 || y > 1 {
+			t.Errorf("tile %d/%d/%d requested", z, x, y)
+		}
+		if y == 1 {
+			return nil, ErrTileNotFound
+		}
+		// Uniform images are unbounded; the map clips them to the tile.
+		return image.NewUniform(colors[x]), nil
+	})
+
+	// Centred on the antimeridian, the view shows tile 1 then tile 0.
+	m := NewMap(512, 256, WithView(go_huawei.Coordinate{Lng: 180}, 1.6), WithTileProvider(provider))
+	img, err := m.Render(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requested == 0 {
+		t.Fatal("no tiles requested")
+	}
+	if got := img.RGBAAt(10, 10); got != rgba(colors[1]) {
+		t.Errorf("west of the antimeridian = %v, want tile 1", got)
+	}
+	if got := img.RGBAAt(500, 10); got != rgba(colors[0]) {
+		t.Errorf("east of the antimeridian = %v, want tile 0", got)
+	}
+	if got := img.RGBAAt(10, 250); got != rgba(DefaultStyle().Background) {
+		t.Errorf("missing tile = %v, want the background", got)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := NewMap(0, 10).Render(context.Background()); err == nil || err.Error() != "render: invalid size 0x10" {
+		t.Errorf("zero width: err = %v", err)
+	}
+
+	// Twice the padding must be less than each side; a fixed view ignores it.
+	path := equatorPath(0)
+	for _, m := range []*Map{NewMap(40, 100), NewMap(100, 30, WithPadding(15)), NewMap(100, 100, WithPadding(60))} {
+		if _, err := m.AddPath(path).Render(context.Background()); err == nil || !strings.HasPrefix(err.Error(), "render: padding ") {
+			t.Errorf("%dx%d with padding %d: err = %v", m.width, m.height, m.padding, err)
+		}
+		if err := m.EncodeSVG(io.Discard); err == nil {
+			t.Errorf("%dx%d with padding %d: EncodeSVG succeeded", m.width, m.height, m.padding)
+		}
+	}
+	if _, err := NewMap(40, 40, WithView(go_huawei.Coordinate{}, 3)).AddPath(path).Render(context.Background()); err != nil {
+		t.Errorf("fixed view with the default padding: err = %v", err)
+	}
+	if _, err := NewMap(41, 41).AddPath(path).Render(context.Background()); err != nil {
+		t.Errorf("one pixel of content: err = %v", err)
+	}
+
+	failing := TileProviderFunc(func(context.Context, int, int, int) (image.Image, error) {
+		return nil, errors.New("server down")
+	})
+	_, err := NewMap(256, 256, WithView(go_huawei.Coordinate{}, 0), WithTileProvider(failing)).Render(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), "render: tile ") || !strings.HasSuffix(err.Error(), "server down") {
+		t.Errorf("failing provider: err = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewMap(256, 256, WithTileProvider(failing)).Render(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled render: err = %v", err)
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewMap(64, 32, WithPadding(4)).AddPath(equatorPath(0)).EncodePNG(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(64, 32) {
+		t.Errorf("PNG size = %v", size)
+	}
+}
+
+func TestDirectoryTileProvider(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "3", "4")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tile := image.NewNRGBA(image.Rect(0, 0, TileSize, TileSize))
+	tile.SetNRGBA(1, 2, color.NRGBA{G: 0xff, A: 0x80})
+	f, err := os.Create(filepath.Join(dir, "5.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, tile); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	provider := DirectoryTileProvider{Root: root}
+	img, err := provider.Tile(context.Background(), 3, 4, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := color.NRGBAModel.Convert(img.At(1, 2)); got != (color.NRGBA{G: 0xff, A: 0x80}) {
+		t.Errorf("pixel = %v", got)
+	}
+
+	if _, err := provider.Tile(context.Background(), 3, 4, 6); !errors.Is(err, ErrTileNotFound) {
+		t.Errorf("missing tile: err = %v", err)
+	}
+	if _, err := (DirectoryTileProvider{Root: root, Extension: ".jpg"}).Tile(context.Background(), 3, 4, 5); !errors.Is(err, ErrTileNotFound) {
+		t.Errorf("other extension: err = %v", err)
+	}
+}
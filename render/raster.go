@@ -0,0 +1,103 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// point is a position on the image, in pixels.
+type point struct {
+	x, y float64
+}
+
+// coverage accumulates anti-aliased shapes into an alpha mask so that a shape
+// made of overlapping parts, such as a polyline, is composited only once.
+type coverage struct {
+	mask *image.Alpha
+}
+
+func newCoverage(bounds image.Rectangle) *coverage {
+	return &coverage{mask: image.NewAlpha(bounds)}
+}
+
+func (c *coverage) add(x, y int, value float64) {
+	if value <= 0 || !(image.Point{X: x, Y: y}).In(c.mask.Rect) {
+		return
+	}
+
+	a := uint8(math.Min(1, value) * 0xff)
+	i := c.mask.PixOffset(x, y)
+	if a > c.mask.Pix[i] {
+		c.mask.Pix[i] = a
+	}
+}
+
+// line adds the segment a-b with round caps.
+func (c *coverage) line(a, b point, width float64) {
+	half := width / 2
+	r := image.Rect(
+		int(math.Floor(math.Min(a.x, b.x)-half-1)), int(math.Floor(math.Min(a.y, b.y)-half-1)),
+		int(math.Ceil(math.Max(a.x, b.x)+half+1)), int(math.Ceil(math.Max(a.y, b.y)+half+1)),
+	).Intersect(c.mask.Rect)
+
+	dx, dy := b.x-a.x, b.y-a.y
+	length2 := dx*dx + dy*dy
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+
+			t := 0.0
+			if length2 > 0 {
+				t = math.Max(0, math.Min(1, ((px-a.x)*dx+(py-a.y)*dy)/length2))
+			}
+			d := math.Hypot(px-(a.x+t*dx), py-(a.y+t*dy))
+			c.add(x, y, half+0.5-d)
+		}
+	}
+}
+
+// polyline adds the segments joining points.
+func (c *coverage) polyline(points []point, width float64) {
+	if len(points) == 1 {
+		c.circle(points[0], width/2)
+		return
+	}
+
+	for i := 1; i < len(points); i++ {
+		c.line(points[i-1], points[i], width)
+	}
+}
+
+// circle adds a filled disc.
+func (c *coverage) circle(center point, radius float64) {
+	r := image.Rect(
+		int(math.Floor(center.x-radius-1)), int(math.Floor(center.y-radius-1)),
+		int(math.Ceil(center.x+radius+1)), int(math.Ceil(center.y+radius+1)),
+	).Intersect(c.mask.Rect)
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			d := math.Hypot(float64(x)+0.5-center.x, float64(y)+0.5-center.y)
+			c.add(x, y, radius+0.5-d)
+		}
+	}
+}
+
+// fill composites col through the mask onto dst.
+func (c *coverage) fill(dst draw.Image, col color.Color) {
+	draw.DrawMask(dst, c.mask.Rect, image.NewUniform(col), image.Point{}, c.mask, c.mask.Rect.Min, draw.Over)
+}
+
+func strokePolyline(dst draw.Image, points []point, width float64, col color.Color) {
+	c := newCoverage(dst.Bounds())
+	c.polyline(points, width)
+	c.fill(dst, col)
+}
+
+func fillCircle(dst draw.Image, center point, radius float64, col color.Color) {
+	c := newCoverage(dst.Bounds())
+	c.circle(center, radius)
+	c.fill(dst, col)
+}
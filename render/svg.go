@@ -0,0 +1,373 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+// Coloring selects how SVG path segments are coloured.
+type Coloring int
+
+const (
+	// ColorByPath colours every path with its Style.Colors entry.
+	ColorByPath = Coloring(0)
+	// ColorByAction colours every step by the maneuver that starts it.
+	ColorByAction = Coloring(1)
+	// ColorByTraffic colours paths by DurationInTraffic over Duration. The API
+	// reports traffic per path, so all steps of a path share its colour.
+	ColorByTraffic = Coloring(2)
+)
+
+// Traffic colours of ColorByTraffic.
+var (
+	TrafficFree      = color.NRGBA{R: 0x1e, G: 0x8e, B: 0x3e, A: 0xff}
+	TrafficSlow      = color.NRGBA{R: 0xf2, G: 0x99, B: 0x00, A: 0xff}
+	TrafficCongested = color.NRGBA{R: 0xd9, G: 0x30, B: 0x25, A: 0xff}
+)
+
+// Ratios of DurationInTraffic over Duration from which traffic counts as slow
+// and congested.
+const (
+	slowRatio      = 1.15
+	congestedRatio = 1.5
+)
+
+// ActionColors are the segment colours of ColorByAction. Actions missing from
+// the map use the first Style colour.
+var ActionColors = map[go_huawei.Action]color.Color{
+	go_huawei.Straight:        color.NRGBA{R: 0x1a, G: 0x73, B: 0xe8, A: 0xff},
+	go_huawei.TurnLeft:        color.NRGBA{R: 0x9c, G: 0x27, B: 0xb0, A: 0xff},
+	go_huawei.TurnRight:       color.NRGBA{R: 0xe3, G: 0x74, B: 0x00, A: 0xff},
+	go_huawei.TurnSlightLeft:  color.NRGBA{R: 0xba, G: 0x68, B: 0xc8, A: 0xff},
+	go_huawei.TurnSlightRight: color.NRGBA{R: 0xf6, G: 0xa0, B: 0x4d, A: 0xff},
+	go_huawei.ForkLeft:        color.NRGBA{R: 0x00, G: 0x89, B: 0x7b, A: 0xff},
+	go_huawei.ForkRight:       color.NRGBA{R: 0x00, G: 0x89, B: 0x7b, A: 0xff},
+	go_huawei.RampLeft:        color.NRGBA{R: 0x5d, G: 0x40, B: 0x37, A: 0xff},
+	go_huawei.RampRight:       color.NRGBA{R: 0x5d, G: 0x40, B: 0x37, A: 0xff},
+	go_huawei.RoundaboutLeft:  color.NRGBA{R: 0xc2, G: 0x18, B: 0x5b, A: 0xff},
+	go_huawei.RoundaboutRight: color.NRGBA{R: 0xc2, G: 0x18, B: 0x5b, A: 0xff},
+	go_huawei.End:             color.NRGBA{R: 0xd9, G: 0x30, B: 0x25, A: 0xff},
+	go_huawei.TurnSharpLeft:   color.NRGBA{R: 0x6a, G: 0x1b, B: 0x9a, A: 0xff},
+	go_huawei.TurnSharpRight:  color.NRGBA{R: 0xbf, G: 0x36, B: 0x0c, A: 0xff},
+	go_huawei.UTurnLeft:       color.NRGBA{R: 0x88, G: 0x0e, B: 0x4f, A: 0xff},
+	go_huawei.UTurnRight:      color.NRGBA{R: 0x88, G: 0x0e, B: 0x4f, A: 0xff},
+	go_huawei.Merge:           color.NRGBA{R: 0x43, G: 0xa0, B: 0x47, A: 0xff},
+	go_huawei.Ferry:           color.NRGBA{R: 0x03, G: 0x9b, B: 0xe5, A: 0xff},
+	go_huawei.FerryTrain:      color.NRGBA{R: 0x03, G: 0x9b, B: 0xe5, A: 0xff},
+}
+
+// SVGOption is the type of options for Map.EncodeSVG(...).
+type SVGOption func(*svgConfig)
+
+// WithColoring selects how segments are coloured. Default is ColorByPath.
+func WithColoring(coloring Coloring) SVGOption {
+	return func(c *svgConfig) {
+		c.coloring = coloring
+	}
+}
+
+// WithoutRoadLabels omits the road name labels.
+func WithoutRoadLabels() SVGOption {
+	return func(c *svgConfig) {
+		c.noLabels = true
+	}
+}
+
+// WithManeuverGlyphs draws an arrow glyph of the given size, in pixels, at the
+// start of every step of the first path. The size must be positive.
+func WithManeuverGlyphs(size float64) SVGOption {
+	return func(c *svgConfig) {
+		c.glyphs = true
+		c.glyphSize = size
+	}
+}
+
+type svgConfig struct {
+	coloring  Coloring
+	noLabels  bool
+	glyphs    bool
+	glyphSize float64
+}
+
+// EncodeSVG writes the map as an SVG document. Paths are drawn step by step so
+// that segments can be coloured individually; tiles are not included.
+func (m *Map) EncodeSVG(w io.Writer, options ...SVGOption) error {
+	cfg := &svgConfig{}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	if err := m.validate(); err != nil {
+		return err
+	}
+	if cfg.glyphs && !(cfg.glyphSize > 0) {
+		return fmt.Errorf("render: invalid glyph size %v", cfg.glyphSize)
+	}
+
+	v := m.viewport()
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", m.width, m.height, m.width, m.height)
+	if m.style.Background != nil {
+		fmt.Fprintf(b, `<rect width="100%%" height="100%%" %s/>`+"\n", svgPaint("fill", m.style.Background))
+	}
+
+	for _, bounds := range m.bounds {
+		m.svgBounds(b, v, bounds)
+	}
+
+	b.WriteString(`<g fill="none" stroke-linecap="round" stroke-linejoin="round">` + "\n")
+	for i := len(m.paths) - 1; i >= 0; i-- {
+		m.svgPath(b, v, i, cfg)
+	}
+	b.WriteString("</g>\n")
+
+	if !cfg.noLabels && len(m.paths) > 0 {
+		m.svgLabels(b, v, m.paths[0])
+	}
+
+	if cfg.glyphs && len(m.paths) > 0 {
+		for _, step := range m.paths[0].Steps {
+			p := v.point(step.StartLocation)
+			fmt.Fprintf(b, `<g transform="translate(%s %s)">%s</g>`+"\n",
+				svgFloat(p.x-cfg.glyphSize/2), svgFloat(p.y-cfg.glyphSize/2), maneuverGlyph(step, cfg.glyphSize, true))
+		}
+	}
+
+	if len(m.paths) > 0 {
+		first := m.paths[0]
+		svgPin(b, v.point(first.StartLocation), m.style.PinRadius, m.style.StartColor)
+		svgPin(b, v.point(first.EndLocation), m.style.PinRadius, m.style.EndColor)
+	}
+
+	for _, marker := range m.markers {
+		radius := marker.Radius
+		if radius == 0 {
+			radius = m.style.PinRadius
+		}
+		svgPin(b, v.point(marker.Location), radius, marker.Color)
+	}
+
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+func (m *Map) svgPath(w io.Writer, v viewport, i int, cfg *svgConfig) {
+	path := m.paths[i]
+
+	var segments [][]point
+	var colors []color.Color
+	for _, step := range path.Steps {
+		polyline := go_huawei.RemoveDuplicates(step.Polyline)
+		if len(polyline) == 0 {
+			continue
+		}
+
+		segments = append(segments, v.project(polyline))
+		colors = append(colors, m.segmentColor(path, i, step, cfg.coloring))
+	}
+
+	if m.style.Casing != nil && m.style.CasingWidth > 0 {
+		for _, points := range segments {
+			fmt.Fprintf(w, `<polyline points="%s" %s stroke-width="%s"/>`+"\n",
+				svgPoints(points), svgPaint("stroke", m.style.Casing), svgFloat(m.style.LineWidth+m.style.CasingWidth))
+		}
+	}
+
+	for j, points := range segments {
+		fmt.Fprintf(w, `<polyline points="%s" %s stroke-width="%s"/>`+"\n",
+			svgPoints(points), svgPaint("stroke", colors[j]), svgFloat(m.style.LineWidth))
+	}
+}
+
+func (m *Map) segmentColor(path *go_huawei.Path, i int, step go_huawei.Step, coloring Coloring) color.Color {
+	switch coloring {
+	case ColorByAction:
+		if c, ok := ActionColors[step.Action]; ok {
+			return c
+		}
+		return m.pathColor(0)
+	case ColorByTraffic:
+		return trafficColor(path)
+	}
+
+	return m.pathColor(i)
+}
+
+func trafficColor(path *go_huawei.Path) color.Color {
+	if path.Duration <= 0 || path.DurationInTraffic <= 0 {
+		return TrafficFree
+	}
+
+	switch ratio := path.DurationInTraffic / path.Duration; {
+	case ratio >= congestedRatio:
+		return TrafficCongested
+	case ratio >= slowRatio:
+		return TrafficSlow
+	}
+
+	return TrafficFree
+}
+
+// svgLabels writes the road name of every step where it changes, at the middle
+// of the step and aligned with its overall direction.
+func (m *Map) svgLabels(w io.Writer, v viewport, path *go_huawei.Path) {
+	fmt.Fprint(w, `<g font-family="sans-serif" font-size="11" text-anchor="middle" fill="#202124" stroke="#ffffff" stroke-width="3" paint-order="stroke">`+"\n")
+
+	previous := ""
+	for _, step := range path.Steps {
+		if step.RoadName == "" || step.RoadName == previous {
+			continue
+		}
+		previous = step.RoadName
+
+		points := v.project(go_huawei.RemoveDuplicates(step.Polyline))
+		if len(points) < 2 {
+			continue
+		}
+
+		// Skip steps too short on screen to hold a label.
+		a, b := points[0], points[len(points)-1]
+		if math.Hypot(b.x-a.x, b.y-a.y) < float64(len([]rune(step.RoadName)))*6 {
+			continue
+		}
+
+		angle := math.Atan2(b.y-a.y, b.x-a.x) * 180 / math.Pi
+		// Keep text upright.
+		if angle > 90 {
+			angle -= 180
+		} else if angle < -90 {
+			angle += 180
+		}
+
+		middle := points[len(points)/2]
+		x, y := middle.x, middle.y
+		fmt.Fprintf(w, `<text x="%s" y="%s" dy="-6" transform="rotate(%s %s %s)">%s</text>`+"\n",
+			svgFloat(x), svgFloat(y), svgFloat(angle), svgFloat(x), svgFloat(y), html.EscapeString(step.RoadName))
+	}
+
+	fmt.Fprint(w, "</g>\n")
+}
+
+func (m *Map) svgBounds(w io.Writer, v viewport, bounds go_huawei.CoordinateBounds) {
+	if m.style.BoundsColor == nil || bounds.IsEmpty() {
+		return
+	}
+
+	sw, ne := v.point(bounds.Southwest), v.point(bounds.Northeast)
+	if ne.x < sw.x {
+		ne.x += v.worldSize
+	}
+
+	fmt.Fprintf(w, `<rect x="%s" y="%s" width="%s" height="%s" fill="none" %s stroke-width="%s"/>`+"\n",
+		svgFloat(sw.x), svgFloat(ne.y), svgFloat(ne.x-sw.x), svgFloat(sw.y-ne.y),
+		svgPaint("stroke", m.style.BoundsColor), svgFloat(m.style.BoundsWidth))
+}
+
+func svgPin(w io.Writer, p point, radius float64, col color.Color) {
+	if col == nil || radius <= 0 {
+		return
+	}
+
+	fmt.Fprintf(w, `<circle cx="%s" cy="%s" r="%s" %s stroke="#ffffff" stroke-width="2"/>`+"\n",
+		svgFloat(p.x), svgFloat(p.y), svgFloat(radius), svgPaint("fill", col))
+}
+
+func svgPoints(points []point) string {
+	pairs := make([]string, len(points))
+	for i, p := range points {
+		pairs[i] = svgFloat(p.x) + "," + svgFloat(p.y)
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// svgPaint returns a fill or stroke attribute, with its opacity when the colour
+// is translucent.
+func svgPaint(attribute string, c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	paint := fmt.Sprintf(`%s="#%02x%02x%02x"`, attribute, n.R, n.G, n.B)
+	if n.A != 0xff {
+		paint += fmt.Sprintf(` %s-opacity="%s"`, attribute, svgFloat(float64(n.A)/0xff))
+	}
+
+	return paint
+}
+
+func svgFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// ManeuverGlyph returns a standalone SVG document, size pixels square, with an
+// arrow depicting the step's maneuver. Unknown maneuvers get the straight
+// arrow. See WriteManeuverGlyph.
+func ManeuverGlyph(step go_huawei.Step, size float64) (string, error) {
+	if !(size > 0) {
+		return "", fmt.Errorf("render: invalid glyph size %v", size)
+	}
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">%s</svg>`,
+		svgFloat(size), svgFloat(size), svgFloat(size), svgFloat(size), maneuverGlyph(step, size, false)), nil
+}
+
+// WriteManeuverGlyph writes the ManeuverGlyph of the step to w.
+func WriteManeuverGlyph(w io.Writer, step go_huawei.Step, size float64) error {
+	glyph, err := ManeuverGlyph(step, size)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, glyph)
+	return err
+}
+
+// glyphPaths are arrow outlines in a 24×24 box with travel pointing up.
+var glyphPaths = map[go_huawei.Action]string{
+	go_huawei.Straight:        "M12 21V5M7 10l5-5 5 5",
+	go_huawei.TurnLeft:        "M16 21V12a3 3 0 0 0-3-3H5M9 5L5 9l4 4",
+	go_huawei.TurnRight:       "M8 21V12a3 3 0 0 1 3-3h8M15 5l4 4-4 4",
+	go_huawei.TurnSlightLeft:  "M15 21v-7l-7-7M8 12V7h5",
+	go_huawei.TurnSlightRight: "M9 21v-7l7-7M11 7h5v5",
+	go_huawei.ForkLeft:        "M12 21v-7l-5-6M12 14l5-6M4 10V6h4",
+	go_huawei.ForkRight:       "M12 21v-7l5-6M12 14l-5-6M16 6h4v4",
+	go_huawei.RampLeft:        "M14 21V9M14 14c-4 0-7-3-7-7M4 10l3-4 3 4",
+	go_huawei.RampRight:       "M10 21V9M10 14c4 0 7-3 7-7M14 10l3-4 3 4",
+	go_huawei.RoundaboutLeft:  "M12 21v-5M12 16a4 4 0 1 0 0-8 4 4 0 0 0-4 4H4M7 9l-3 3 3 3",
+	go_huawei.RoundaboutRight: "M12 21v-5M12 16a4 4 0 1 1 0-8 4 4 0 0 1 4 4h4M17 9l3 3-3 3",
+	go_huawei.End:             "M12 21v-7M12 14a4 4 0 1 1 0-8 4 4 0 0 1 0 8z",
+	go_huawei.TurnSharpLeft:   "M16 21V7L6 17M6 11v6h6",
+	go_huawei.TurnSharpRight:  "M8 21V7l10 10M18 11v6h-6",
+	go_huawei.UTurnLeft:       "M16 21V9a4 4 0 0 0-8 0v10M5 16l3 3 3-3",
+	go_huawei.UTurnRight:      "M8 21V9a4 4 0 0 1 8 0v10M13 16l3 3 3-3",
+	go_huawei.Merge:           "M7 21c0-6 5-7 5-11M17 21c0-6-5-7-5-11M12 10V4M8 8l4-4 4 4",
+	go_huawei.Ferry:           "M4 15h16l-2 5H6zM7 15V10h10v5M12 10V5",
+	go_huawei.FerryTrain:      "M4 15h16l-2 5H6zM6 15v-5h12v5M9 10V7h6v3",
+}
+
+// maneuverGlyph returns the glyph elements for the step, scaled to size. With
+// heading set the glyph is rotated by Orientation, read as degrees clockwise
+// from north, so that it matches the map.
+func maneuverGlyph(step go_huawei.Step, size float64, heading bool) string {
+	d, ok := glyphPaths[step.Action]
+	if !ok {
+		d = glyphPaths[go_huawei.Straight]
+	}
+
+	transform := "scale(" + svgFloat(size/24) + ")"
+	if heading && step.Orientation != 0 {
+		transform += " rotate(" + strconv.FormatInt(step.Orientation, 10) + " 12 12)"
+	}
+
+	return `<g transform="` + transform + `">` +
+		`<circle cx="12" cy="12" r="11.5" fill="#ffffff" fill-opacity="0.9"/>` +
+		`<path d="` + d + `" fill="none" stroke="#202124" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"/>` +
+		`</g>`
+}
@@ -0,0 +1,167 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+var strokePattern = regexp.MustCompile(`<polyline points="[^"]*" stroke="(#[0-9a-f]{6})"`)
+
+// trafficMap holds two paths on the equator: the first, in heavy traffic,
+// turns left and then makes an unknown maneuver; the second, in slow traffic,
+// goes straight.
+func trafficMap() *Map {
+	style := DefaultStyle()
+	style.Colors = []color.Color{color.NRGBA{R: 0xff, A: 0xff}, color.NRGBA{G: 0xff, A: 0xff}}
+	style.Casing = nil
+
+	first := &go_huawei.Path{
+		Duration:          100,
+		DurationInTraffic: 160,
+		Steps: []go_huawei.Step{
+			{Action: go_huawei.TurnLeft, Polyline: []go_huawei.Coordinate{{Lng: -1}, {}}},
+			{Action: go_huawei.Action("hover"), Polyline: []go_huawei.Coordinate{{}, {Lng: 1}}},
+		},
+	}
+	second := equatorPath(0.5)
+	second.Duration, second.DurationInTraffic = 100, 120
+	second.Steps[0].Action = go_huawei.Straight
+
+	return NewMap(400, 200, WithStyle(style)).AddPath(first).AddPath(second)
+}
+
+func encodeSVG(t *testing.T, m *Map, options ...SVGOption) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := m.EncodeSVG(&buf, options...); err != nil {
+		t.Fatal(err)
+	}
+
+	// The document must be well-formed XML.
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, buf.String())
+		}
+	}
+
+	return buf.String()
+}
+
+func TestSVGColoring(t *testing.T) {
+	// Paths are drawn last to first, so the second path comes first.
+	tests := []struct {
+		name     string
+		coloring Coloring
+		want     []string
+	}{
+		{"by path", ColorByPath, []string{"#00ff00", "#ff0000", "#ff0000"}},
+		{"by action", ColorByAction, []string{"#1a73e8", "#9c27b0", "#ff0000"}},
+		{"by traffic", ColorByTraffic, []string{"#f29900", "#d93025", "#d93025"}},
+	}
+
+	for _, test := range tests {
+		svg := encodeSVG(t, trafficMap(), WithColoring(test.coloring))
+
+		var got []string
+		for _, match := range strokePattern.FindAllStringSubmatch(svg, -1) {
+			got = append(got, match[1])
+		}
+		if strings.Join(got, " ") != strings.Join(test.want, " ") {
+			t.Errorf("%s: strokes = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSVGLabels(t *testing.T) {
+	path := equatorPath(0)
+	path.Steps[0].RoadName = `Shevchenka & "Lesi" <Ukrainky>`
+
+	svg := encodeSVG(t, NewMap(400, 200).AddPath(path))
+	if !strings.Contains(svg, `>Shevchenka &amp; &#34;Lesi&#34; &lt;Ukrainky&gt;</text>`) {
+		t.Errorf("label is not escaped:\n%s", svg)
+	}
+
+	if svg := encodeSVG(t, NewMap(400, 200).AddPath(path), WithoutRoadLabels()); strings.Contains(svg, "<text") {
+		t.Errorf("labels drawn despite WithoutRoadLabels:\n%s", svg)
+	}
+}
+
+func TestSVGManeuverGlyphs(t *testing.T) {
+	path := equatorPath(0)
+	path.Steps[0].Orientation = 90
+	path.Steps = append(path.Steps, go_huawei.Step{Action: go_huawei.End, StartLocation: path.EndLocation})
+
+	svg := encodeSVG(t, NewMap(400, 200).AddPath(path), WithManeuverGlyphs(18), WithoutRoadLabels())
+	if n := strings.Count(svg, `<g transform="scale(0.75)`); n != 2 {
+		t.Errorf("drew %d glyphs, want 2:\n%s", n, svg)
+	}
+	if n := strings.Count(svg, "rotate("); n != 1 || !strings.Contains(svg, `scale(0.75) rotate(90 12 12)`) {
+		t.Errorf("want only the first glyph turned east:\n%s", svg)
+	}
+}
+
+func TestManeuverGlyph(t *testing.T) {
+	glyph, err := ManeuverGlyph(go_huawei.Step{Action: go_huawei.TurnRight, Orientation: 45}, 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(glyph, `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48"`) ||
+		!strings.Contains(glyph, `d="`+glyphPaths[go_huawei.TurnRight]+`"`) {
+		t.Errorf("turn-right glyph = %s", glyph)
+	}
+	// A standalone glyph points up whatever the heading.
+	if strings.Contains(glyph, "rotate(") {
+		t.Errorf("standalone glyph is rotated: %s", glyph)
+	}
+
+	unknown, err := ManeuverGlyph(go_huawei.Step{Action: go_huawei.Action("hover")}, 48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(unknown, `d="`+glyphPaths[go_huawei.Straight]+`"`) {
+		t.Errorf("unknown action glyph = %s, want the straight arrow", unknown)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteManeuverGlyph(&buf, go_huawei.Step{Action: go_huawei.TurnRight, Orientation: 45}, 48); err != nil || buf.String() != glyph {
+		t.Errorf("WriteManeuverGlyph = %q, %v; want %q", buf.String(), err, glyph)
+	}
+}
+
+func TestSVGSizeErrors(t *testing.T) {
+	step := go_huawei.Step{Action: go_huawei.TurnLeft}
+
+	for _, size := range []float64{0, -18, math.NaN()} {
+		if glyph, err := ManeuverGlyph(step, size); err == nil {
+			t.Errorf("ManeuverGlyph(%v) = %q, want an error", size, glyph)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteManeuverGlyph(&buf, step, size); err == nil || buf.Len() != 0 {
+			t.Errorf("WriteManeuverGlyph(%v) wrote %q, %v; want an error", size, buf.String(), err)
+		}
+
+		if err := trafficMap().EncodeSVG(io.Discard, WithManeuverGlyphs(size)); err == nil || !strings.HasPrefix(err.Error(), "render: invalid glyph size") {
+			t.Errorf("EncodeSVG with glyph size %v: err = %v", size, err)
+		}
+	}
+
+	for _, m := range []*Map{NewMap(0, 200), NewMap(400, -1)} {
+		var buf bytes.Buffer
+		if err := m.EncodeSVG(&buf); err == nil || buf.Len() != 0 {
+			t.Errorf("EncodeSVG of %dx%d wrote %d bytes, %v; want an error", m.width, m.height, buf.Len(), err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"image"
+	_ "image/jpeg" // tile formats
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/stremovskyy/go-huawei/projection"
+)
+
+// TileSize is the width and height of a map tile, in pixels.
+const TileSize = projection.TileSize
+
+// ErrTileNotFound may be returned by a TileProvider for tiles it does not have;
+// the map leaves their area to the background colour.
+var ErrTileNotFound = errors.New("render: tile not found")
+
+// TileProvider supplies 256×256 Web Mercator background tiles in the XYZ
+// scheme used by OpenStreetMap.
+type TileProvider interface {
+	Tile(ctx context.Context, z, x, y int) (image.Image, error)
+}
+
+// TileProviderFunc adapts a function to the TileProvider interface.
+type TileProviderFunc func(ctx context.Context, z, x, y int) (image.Image, error)
+
+// Tile calls f(ctx, z, x, y).
+func (f TileProviderFunc) Tile(ctx context.Context, z, x, y int) (image.Image, error) {
+	return f(ctx, z, x, y)
+}
+
+// DirectoryTileProvider reads tiles from a {z}/{x}/{y}.png tree on disk, such as
+// one exported for offline use.
+type DirectoryTileProvider struct {
+	Root string
+	// Extension of the tile files. Default is ".png".
+	Extension string
+}
+
+// Tile reads the tile file. Missing files yield ErrTileNotFound.
+func (p DirectoryTileProvider) Tile(_ context.Context, z, x, y int) (image.Image, error) {
+	extension := p.Extension
+	if extension == "" {
+		extension = ".png"
+	}
+
+	f, err := os.Open(filepath.Join(p.Root, strconv.Itoa(z), strconv.Itoa(x), strconv.Itoa(y)+extension))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrTileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
@@ -0,0 +1,12 @@
+package go_huawei
+
+import "context"
+
+// DirectionsService is the Directions capability of the Client. Depend on it
+// instead of *Client to substitute a mock in tests.
+type DirectionsService interface {
+	// Directions issues the Directions request and retrieves the Response
+	Directions(ctx context.Context, directionsRequest *DirectionsRequest) ([]Route, error)
+}
+
+var _ DirectionsService = (*Client)(nil)
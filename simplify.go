@@ -0,0 +1,254 @@
+package go_huawei
+
+import (
+	"container/heap"
+	"math"
+)
+
+// planarPoint is a coordinate projected onto a local plane, in metres.
+type planarPoint struct {
+	x, y float64
+}
+
+// localProjection is an equirectangular projection centred on a polyline. It
+// is accurate enough for tolerances of a route's scale.
+type localProjection struct {
+	origin Coordinate
+	cosLat float64
+}
+
+func newLocalProjection(coords []Coordinate) localProjection {
+	var lat float64
+	for _, c := range coords {
+		lat += c.Lat
+	}
+	if len(coords) > 0 {
+		lat /= float64(len(coords))
+	}
+
+	p := localProjection{cosLat: math.Cos(toRadians(lat))}
+	if len(coords) > 0 {
+		p.origin = coords[0]
+	}
+
+	return p
+}
+
+func (p localProjection) project(c Coordinate) planarPoint {
+	return planarPoint{
+		x: toRadians(normalizeLng(c.Lng-p.origin.Lng)) * p.cosLat * EarthRadius,
+		y: toRadians(c.Lat-p.origin.Lat) * EarthRadius,
+	}
+}
+
+func (p localProjection) projectAll(coords []Coordinate) []planarPoint {
+	points := make([]planarPoint, len(coords))
+	for i, c := range coords {
+		points[i] = p.project(c)
+	}
+
+	return points
+}
+
+// RemoveDuplicates returns coords without consecutive repeated vertices, such
+// as the joints Path.Overview() repeats at step boundaries.
+func RemoveDuplicates(coords []Coordinate) []Coordinate {
+	if len(coords) == 0 {
+		return coords
+	}
+
+	unique := make([]Coordinate, 0, len(coords))
+	unique = append(unique, coords[0])
+	for _, c := range coords[1:] {
+		last := unique[len(unique)-1]
+		if c.Lat != last.Lat || c.Lng != last.Lng {
+			unique = append(unique, c)
+		}
+	}
+
+	return unique
+}
+
+// SimplifyDouglasPeucker simplifies a polyline with the Douglas-Peucker
+// algorithm, dropping vertices closer than tolerance metres to the simplified
+// line. The first and last vertices are always kept.
+func SimplifyDouglasPeucker(coords []Coordinate, tolerance float64) []Coordinate {
+	coords = RemoveDuplicates(coords)
+	if len(coords) < 3 || tolerance <= 0 {
+		return coords
+	}
+
+	points := newLocalProjection(coords).projectAll(coords)
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	type span struct{ first, last int }
+	stack := []span{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		farthest, maxDistance := -1, tolerance
+		for i := s.first + 1; i < s.last; i++ {
+			if d := segmentDistance(points[i], points[s.first], points[s.last]); d > maxDistance {
+				farthest, maxDistance = i, d
+			}
+		}
+
+		if farthest >= 0 {
+			keep[farthest] = true
+			stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+		}
+	}
+
+	simplified := make([]Coordinate, 0, len(coords))
+	for i, c := range coords {
+		if keep[i] {
+			simplified = append(simplified, c)
+		}
+	}
+
+	return simplified
+}
+
+// SimplifyVisvalingam simplifies a polyline with the Visvalingam-Whyatt
+// algorithm, repeatedly dropping the vertex forming the smallest triangle with
+// its neighbours while that area is below tolerance² square metres. The first
+// and last vertices are always kept.
+func SimplifyVisvalingam(coords []Coordinate, tolerance float64) []Coordinate {
+	coords = RemoveDuplicates(coords)
+	if len(coords) < 3 || tolerance <= 0 {
+		return coords
+	}
+
+	points := newLocalProjection(coords).projectAll(coords)
+	threshold := tolerance * tolerance
+
+	vertices := make([]*vwVertex, len(points))
+	for i := range points {
+		vertices[i] = &vwVertex{index: i, prev: i - 1, next: i + 1}
+	}
+
+	queue := make(vwQueue, 0, len(points)-2)
+	for i := 1; i < len(points)-1; i++ {
+		vertices[i].area = triangleArea(points[i-1], points[i], points[i+1])
+		heap.Push(&queue, vertices[i])
+	}
+
+	removed := make([]bool, len(points))
+	for queue.Len() > 0 {
+		v := heap.Pop(&queue).(*vwVertex)
+		if v.area >= threshold {
+			break
+		}
+
+		removed[v.index] = true
+		prev, next := vertices[v.prev], vertices[v.next]
+		prev.next, next.prev = next.index, prev.index
+
+		// Neighbours never get a smaller area than the removed vertex, so the
+		// removal order matches the effective area order.
+		for _, neighbour := range []*vwVertex{prev, next} {
+			if neighbour.heapIndex < 0 || neighbour.index == 0 || neighbour.index == len(points)-1 {
+				continue
+			}
+
+			area := triangleArea(points[neighbour.prev], points[neighbour.index], points[neighbour.next])
+			neighbour.area = math.Max(area, v.area)
+			heap.Fix(&queue, neighbour.heapIndex)
+		}
+	}
+
+	simplified := make([]Coordinate, 0, len(coords))
+	for i, c := range coords {
+		if !removed[i] {
+			simplified = append(simplified, c)
+		}
+	}
+
+	return simplified
+}
+
+// Resample returns points spaced interval metres apart along the polyline,
+// starting at its first vertex. The last vertex is always included.
+func Resample(coords []Coordinate, interval float64) []Coordinate {
+	coords = RemoveDuplicates(coords)
+	if len(coords) < 2 || interval <= 0 {
+		return coords
+	}
+
+	resampled := []Coordinate{coords[0]}
+	next := interval
+	travelled := 0.0
+	for i := 1; i < len(coords); i++ {
+		from, to := coords[i-1], coords[i]
+		length := from.DistanceTo(to)
+
+		for next <= travelled+length {
+			resampled = append(resampled, from.Interpolate(to, (next-travelled)/length))
+			next += interval
+		}
+
+		travelled += length
+	}
+
+	if last := coords[len(coords)-1]; resampled[len(resampled)-1].DistanceTo(last) > interval*1e-6 {
+		resampled = append(resampled, last)
+	}
+
+	return resampled
+}
+
+// segmentDistance returns the distance from p to the segment a-b.
+func segmentDistance(p, a, b planarPoint) float64 {
+	t := segmentFraction(p, a, b)
+	return math.Hypot(p.x-(a.x+t*(b.x-a.x)), p.y-(a.y+t*(b.y-a.y)))
+}
+
+// segmentFraction returns the fraction of the segment a-b closest to p.
+func segmentFraction(p, a, b planarPoint) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	if dx == 0 && dy == 0 {
+		return 0
+	}
+
+	t := ((p.x-a.x)*dx + (p.y-a.y)*dy) / (dx*dx + dy*dy)
+	return math.Max(0, math.Min(1, t))
+}
+
+func triangleArea(a, b, c planarPoint) float64 {
+	return math.Abs((b.x-a.x)*(c.y-a.y)-(c.x-a.x)*(b.y-a.y)) / 2
+}
+
+type vwVertex struct {
+	index, prev, next int
+	area              float64
+	heapIndex         int
+}
+
+// vwQueue is a min-heap of vertices ordered by effective area.
+type vwQueue []*vwVertex
+
+func (q vwQueue) Len() int { return len(q) }
+
+func (q vwQueue) Less(i, j int) bool { return q[i].area < q[j].area }
+
+func (q vwQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].heapIndex = i
+	q[j].heapIndex = j
+}
+
+func (q *vwQueue) Push(x interface{}) {
+	v := x.(*vwVertex)
+	v.heapIndex = len(*q)
+	*q = append(*q, v)
+}
+
+func (q *vwQueue) Pop() interface{} {
+	old := *q
+	v := old[len(old)-1]
+	v.heapIndex = -1
+	*q = old[:len(old)-1]
+	return v
+}
@@ -0,0 +1,100 @@
+package go_huawei
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// wiggle is a line along the equator with a 1 m kink, a 50 m kink and a
+// repeated vertex.
+var wiggle = []Coordinate{
+	{Lng: 0},
+	{Lng: 0.001, Lat: 1 / equatorDegree},
+	{Lng: 0.002},
+	{Lng: 0.002},
+	{Lng: 0.003, Lat: 50 / equatorDegree},
+	{Lng: 0.004},
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	got := RemoveDuplicates(wiggle)
+	if len(got) != 5 || got[2] != wiggle[2] || got[3] != wiggle[4] {
+		t.Errorf("RemoveDuplicates = %v", got)
+	}
+
+	if got := RemoveDuplicates(nil); got != nil {
+		t.Errorf("RemoveDuplicates(nil) = %v", got)
+	}
+	if got := RemoveDuplicates([]Coordinate{{Lat: 1}, {Lat: 1}}); len(got) != 1 {
+		t.Errorf("RemoveDuplicates of one repeated point = %v", got)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name      string
+		simplify  func([]Coordinate, float64) []Coordinate
+		tolerance float64
+		want      []Coordinate
+	}{
+		{"Douglas-Peucker drops the small kink", SimplifyDouglasPeucker, 10, []Coordinate{wiggle[0], wiggle[2], wiggle[4], wiggle[5]}},
+		{"Douglas-Peucker keeps everything", SimplifyDouglasPeucker, 0.1, RemoveDuplicates(wiggle)},
+		{"Douglas-Peucker drops everything", SimplifyDouglasPeucker, 100, []Coordinate{wiggle[0], wiggle[5]}},
+		{"Visvalingam drops the small kink", SimplifyVisvalingam, 50, []Coordinate{wiggle[0], wiggle[2], wiggle[4], wiggle[5]}},
+		{"Visvalingam drops everything", SimplifyVisvalingam, 1000, []Coordinate{wiggle[0], wiggle[5]}},
+		{"no tolerance", SimplifyVisvalingam, 0, RemoveDuplicates(wiggle)},
+	}
+
+	for _, test := range tests {
+		if got := test.simplify(wiggle, test.tolerance); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSimplifyAcrossAntimeridian(t *testing.T) {
+	line := []Coordinate{{Lng: 179.999}, {Lng: -180, Lat: 1 / equatorDegree}, {Lng: -179.999}}
+
+	for name, simplify := range map[string]func([]Coordinate, float64) []Coordinate{
+		"SimplifyDouglasPeucker": SimplifyDouglasPeucker,
+		"SimplifyVisvalingam":    SimplifyVisvalingam,
+	} {
+		if got := simplify(line, 20); len(got) != 2 {
+			t.Errorf("%s = %v, want the endpoints only", name, got)
+		}
+	}
+}
+
+func TestResample(t *testing.T) {
+	line := []Coordinate{{}, {Lng: 0.01}, {Lng: 0.01}, {Lng: 0.01, Lat: 0.005}}
+	interval := 100.0
+
+	got := Resample(line, interval)
+	length := PathLength(line)
+	if want := int(math.Floor(length/interval)) + 2; len(got) != want {
+		t.Fatalf("Resample returned %d points, want %d", len(got), want)
+	}
+	if got[0] != line[0] || got[len(got)-1] != line[3] {
+		t.Errorf("Resample does not keep the endpoints: %v … %v", got[0], got[len(got)-1])
+	}
+
+	// Points follow the line, so spacing is the interval except round the corner
+	// and at the end.
+	for i := 1; i < len(got)-1; i++ {
+		if d := got[i-1].DistanceTo(got[i]); d > interval+1e-6 {
+			t.Errorf("points %d and %d are %.3f m apart", i-1, i, d)
+		}
+	}
+	if got[1].DistanceTo(line[0]) < interval-1e-6 {
+		t.Errorf("first step = %v", got[1])
+	}
+
+	exact := []Coordinate{{}, {Lng: 2 * interval / equatorDegree}}
+	if got := Resample(exact, interval); len(got) != 3 {
+		t.Errorf("Resample of an exact multiple = %v, want 3 points", got)
+	}
+	if got := Resample(line, 0); len(got) != 3 {
+		t.Errorf("Resample without an interval = %v", got)
+	}
+}
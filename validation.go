@@ -0,0 +1,141 @@
+package go_huawei
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxWaypoints is the number of waypoints supported by each RouteService.
+var maxWaypoints = map[RouteService]int{
+	RouteServiceDriving:   5,
+	RouteServiceWalking:   0,
+	RouteServiceBicycling: 0,
+}
+
+// supportedLanguages are the languages of the distance and journey time
+// descriptions the API can return.
+var supportedLanguages = []string{"zh_CN", "en"}
+
+// FieldError describes a problem with a single request field. Field is the JSON
+// path of the field, e.g. "waypoints[1].lat".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationError reports all the problems found in a request at once.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		problems = append(problems, fieldError.Error())
+	}
+
+	return "map-kit: invalid request: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Validate checks the request against the documented API constraints and returns
+// a *ValidationError listing every problem found, or nil if the request is valid.
+func (r *DirectionsRequest) Validate() error {
+	v := &ValidationError{}
+	if r == nil {
+		v.add("request", "missing")
+		return v
+	}
+
+	validateCoordinate(v, "origin", r.Origin)
+	validateCoordinate(v, "destination", r.Destination)
+
+	routeService := r.routeService()
+	switch routeService {
+	case RouteServiceDriving, RouteServiceWalking, RouteServiceBicycling:
+	default:
+		v.add("routeService", "unknown RouteService '%s'", r.RouteService)
+	}
+
+	for i, waypoint := range r.Waypoints {
+		validateCoordinate(v, fmt.Sprintf("waypoints[%d]", i), waypoint)
+	}
+
+	if limit, ok := maxWaypoints[routeService]; ok && len(r.Waypoints) > limit {
+		if limit == 0 {
+			v.add("waypoints", "not supported by RouteService '%s'", routeService)
+		} else {
+			v.add("waypoints", "%d waypoints exceed the limit of %d for RouteService '%s'", len(r.Waypoints), limit, routeService)
+		}
+	}
+
+	if r.Alternatives && len(r.Waypoints) > 0 {
+		v.add("alternatives", "not supported when waypoints are set")
+	}
+
+	if r.TrafficMode < TrafficModeBestGuess || r.TrafficMode > TrafficModeOptimistic {
+		v.add("trafficMode", "unknown TrafficMode %d", r.TrafficMode)
+	}
+
+	seen := make(map[Avoid]bool, len(r.Avoid))
+	for i, avoid := range r.Avoid {
+		switch {
+		case avoid != AvoidTolls && avoid != AvoidHighways:
+			v.add(fmt.Sprintf("avoid[%d]", i), "unknown Avoid %d", avoid)
+		case seen[avoid]:
+			v.add(fmt.Sprintf("avoid[%d]", i), "duplicate Avoid %d", avoid)
+		}
+		seen[avoid] = true
+	}
+
+	if r.DepartAt != 0 && int64(r.DepartAt) < time.Now().Unix() {
+		v.add("departAt", "%d is in the past", r.DepartAt)
+	}
+
+	if r.Language != "" && !isSupportedLanguage(r.Language) {
+		v.add("language", "unsupported language '%s', expected one of %s", r.Language, strings.Join(supportedLanguages, ", "))
+	}
+
+	return v.errOrNil()
+}
+
+func validateCoordinate(v *ValidationError, field string, c *Coordinate) {
+	if c.isEmpty() {
+		v.add(field, "missing")
+		return
+	}
+
+	if !validLatitude(c.Lat) {
+		v.add(field+".lat", "%v out of range [-90, 90]", c.Lat)
+	}
+
+	if !validLongitude(c.Lng) {
+		v.add(field+".lng", "%v out of range [-180, 180]", c.Lng)
+	}
+}
+
+func isSupportedLanguage(language string) bool {
+	for _, supported := range supportedLanguages {
+		if language == supported {
+			return true
+		}
+	}
+
+	return false
+}
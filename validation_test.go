@@ -0,0 +1,135 @@
+package go_huawei
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func validRequest() DirectionsRequest {
+	return DirectionsRequest{
+		Origin:      &Coordinate{Lat: 50.0389, Lng: 36.2187},
+		Destination: &Coordinate{Lat: 49.9935, Lng: 36.2304},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *DirectionsRequest)
+		want   []FieldError
+	}{
+		{
+			name:   "valid",
+			modify: func(*DirectionsRequest) {},
+		},
+		{
+			name:   "zero origin is a coordinate",
+			modify: func(r *DirectionsRequest) { r.Origin = &Coordinate{} },
+		},
+		{
+			name:   "missing endpoints",
+			modify: func(r *DirectionsRequest) { r.Origin, r.Destination = nil, nil },
+			want:   []FieldError{{"origin", "missing"}, {"destination", "missing"}},
+		},
+		{
+			name: "out of range",
+			modify: func(r *DirectionsRequest) {
+				r.Origin = &Coordinate{Lat: 91, Lng: math.NaN()}
+			},
+			want: []FieldError{{"origin.lat", "91 out of range [-90, 90]"}, {"origin.lng", "NaN out of range [-180, 180]"}},
+		},
+		{
+			name:   "unknown route service",
+			modify: func(r *DirectionsRequest) { r.RouteService = "flying" },
+			want:   []FieldError{{"routeService", "unknown RouteService 'flying'"}},
+		},
+		{
+			name: "too many waypoints",
+			modify: func(r *DirectionsRequest) {
+				for i := 0; i < 6; i++ {
+					r.Waypoints = append(r.Waypoints, &Coordinate{Lat: 50, Lng: 36})
+				}
+			},
+			want: []FieldError{{"waypoints", "6 waypoints exceed the limit of 5 for RouteService 'driving'"}},
+		},
+		{
+			name: "waypoints when walking",
+			modify: func(r *DirectionsRequest) {
+				r.RouteService = RouteServiceWalking
+				r.Waypoints = []*Coordinate{nil}
+			},
+			want: []FieldError{{"waypoints[0]", "missing"}, {"waypoints", "not supported by RouteService 'walking'"}},
+		},
+		{
+			name: "alternatives with waypoints",
+			modify: func(r *DirectionsRequest) {
+				r.Alternatives = true
+				r.Waypoints = []*Coordinate{{Lat: 50, Lng: 36}}
+			},
+			want: []FieldError{{"alternatives", "not supported when waypoints are set"}},
+		},
+		{
+			name: "enums",
+			modify: func(r *DirectionsRequest) {
+				r.TrafficMode = 3
+				r.Avoid = []Avoid{AvoidTolls, 7, AvoidTolls}
+			},
+			want: []FieldError{{"trafficMode", "unknown TrafficMode 3"}, {"avoid[1]", "unknown Avoid 7"}, {"avoid[2]", "duplicate Avoid 1"}},
+		},
+		{
+			name: "past departure and language",
+			modify: func(r *DirectionsRequest) {
+				r.DepartAt = 1000
+				r.Language = "uk"
+			},
+			want: []FieldError{{"departAt", "1000 is in the past"}, {"language", "unsupported language 'uk', expected one of zh_CN, en"}},
+		},
+		{
+			name: "future departure",
+			modify: func(r *DirectionsRequest) {
+				r.DepartAt = int(time.Now().Add(time.Hour).Unix())
+				r.Language = "zh_CN"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := validRequest()
+			test.modify(&r)
+
+			err := r.Validate()
+			if test.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var v *ValidationError
+			if !errors.As(err, &v) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			if !reflect.DeepEqual(v.Errors, test.want) {
+				t.Errorf("errors = %q\nwant     %q", v.Errors, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateNil(t *testing.T) {
+	var r *DirectionsRequest
+	if err := r.Validate(); err == nil || err.Error() != "map-kit: invalid request: request: missing" {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := &ValidationError{Errors: []FieldError{{"origin", "missing"}, {"avoid[0]", "unknown Avoid 9"}}}
+	if want := "map-kit: invalid request: origin: missing; avoid[0]: unknown Avoid 9"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,319 @@
+// Package wkb encodes route geometry as OGC Well-Known Binary, and PostGIS
+// EWKB when an SRID is given, and decodes it back.
+//
+// Positions are written in x y order, that is longitude then latitude. The
+// output can be passed to database drivers as a []byte parameter, or as hex
+// via encoding/hex.
+package wkb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/internal/bbox"
+)
+
+// Geometry type codes.
+const (
+	Point      = uint32(1)
+	LineString = uint32(2)
+	Polygon    = uint32(3)
+)
+
+const (
+	bigEndian    = byte(0)
+	littleEndian = byte(1)
+
+	// EWKB flags carried in the high bits of the geometry type.
+	ewkbZ     = uint32(0x80000000)
+	ewkbM     = uint32(0x40000000)
+	ewkbSRID  = uint32(0x20000000)
+	typeFlags = ewkbZ | ewkbM | ewkbSRID
+)
+
+// ErrTruncated is returned when the data ends before the geometry does.
+var ErrTruncated = errors.New("wkb: truncated data")
+
+// Option is the type of options for the Encode functions.
+type Option func(*encoder)
+
+// WithByteOrder sets the byte order, binary.LittleEndian or binary.BigEndian.
+// Default is little endian.
+func WithByteOrder(order binary.ByteOrder) Option {
+	return func(e *encoder) {
+		e.order = order
+	}
+}
+
+// WithSRID embeds the SRID in the geometry, producing EWKB.
+func WithSRID(srid int) Option {
+	return func(e *encoder) {
+		e.srid = srid
+	}
+}
+
+type encoder struct {
+	order binary.ByteOrder
+	srid  int
+	buf   []byte
+}
+
+func newEncoder(options []Option) *encoder {
+	e := &encoder{order: binary.LittleEndian}
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
+}
+
+// EncodePoint returns c as a Point.
+func EncodePoint(c go_huawei.Coordinate, options ...Option) []byte {
+	e := newEncoder(options)
+	e.header(Point)
+	e.position(c)
+
+	return e.buf
+}
+
+// EncodeLineString returns coords, such as Path.Overview() or Step.Polyline,
+// as a LineString.
+func EncodeLineString(coords []go_huawei.Coordinate, options ...Option) []byte {
+	e := newEncoder(options)
+	e.header(LineString)
+	e.sequence(coords)
+
+	return e.buf
+}
+
+// EncodeBounds returns b as a Polygon with a counter-clockwise ring starting at
+// the south-west corner. Bounds crossing the antimeridian get an east edge
+// beyond 180 so that the polygon covers the right side of the globe.
+func EncodeBounds(b go_huawei.CoordinateBounds, options ...Option) []byte {
+	e := newEncoder(options)
+	e.header(Polygon)
+	if b.IsEmpty() {
+		e.uint32(0)
+		return e.buf
+	}
+
+	e.uint32(1)
+	e.sequence(bbox.Ring(b))
+
+	return e.buf
+}
+
+// DecodePoint parses a Point.
+func DecodePoint(data []byte) (go_huawei.Coordinate, error) {
+	d, err := newDecoder(data, Point)
+	if err != nil {
+		return go_huawei.Coordinate{}, err
+	}
+
+	return d.position()
+}
+
+// DecodeLineString parses a LineString.
+func DecodeLineString(data []byte) ([]go_huawei.Coordinate, error) {
+	d, err := newDecoder(data, LineString)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.sequence()
+}
+
+// DecodeBounds parses a Polygon and returns the bounds of its exterior ring.
+// Longitudes beyond 180, as written by EncodeBounds, are wrapped back.
+func DecodeBounds(data []byte) (go_huawei.CoordinateBounds, error) {
+	d, err := newDecoder(data, Polygon)
+	if err != nil {
+		return go_huawei.CoordinateBounds{}, err
+	}
+
+	rings, err := d.uint32()
+	if err != nil || rings == 0 {
+		return go_huawei.CoordinateBounds{}, err
+	}
+
+	ring, err := d.sequence()
+	if err != nil || len(ring) == 0 {
+		return go_huawei.CoordinateBounds{}, err
+	}
+
+	return bbox.FromRing(ring), nil
+}
+
+// SRID returns the SRID of an EWKB geometry and whether it has one.
+func SRID(data []byte) (int, bool) {
+	d := &decoder{data: data}
+	if err := d.byteOrder(); err != nil {
+		return 0, false
+	}
+
+	geometryType, err := d.uint32()
+	if err != nil || geometryType&ewkbSRID == 0 {
+		return 0, false
+	}
+
+	srid, err := d.uint32()
+	if err != nil {
+		return 0, false
+	}
+
+	return int(srid), true
+}
+
+func (e *encoder) header(geometryType uint32) {
+	if e.order == binary.BigEndian {
+		e.buf = append(e.buf, bigEndian)
+	} else {
+		e.buf = append(e.buf, littleEndian)
+	}
+
+	if e.srid == 0 {
+		e.uint32(geometryType)
+		return
+	}
+
+	e.uint32(geometryType | ewkbSRID)
+	e.uint32(uint32(e.srid))
+}
+
+func (e *encoder) sequence(coords []go_huawei.Coordinate) {
+	e.uint32(uint32(len(coords)))
+	for _, c := range coords {
+		e.position(c)
+	}
+}
+
+func (e *encoder) position(c go_huawei.Coordinate) {
+	e.float64(c.Lng)
+	e.float64(c.Lat)
+}
+
+func (e *encoder) uint32(v uint32) {
+	var b [4]byte
+	e.order.PutUint32(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) float64(v float64) {
+	var b [8]byte
+	e.order.PutUint64(b[:], math.Float64bits(v))
+	e.buf = append(e.buf, b[:]...)
+}
+
+type decoder struct {
+	data  []byte
+	order binary.ByteOrder
+	// dimensions is the number of ordinates per position.
+	dimensions int
+}
+
+// newDecoder reads the geometry header and checks its type.
+func newDecoder(data []byte, want uint32) (*decoder, error) {
+	d := &decoder{data: data, dimensions: 2}
+	if err := d.byteOrder(); err != nil {
+		return nil, err
+	}
+
+	geometryType, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	if geometryType&ewkbSRID != 0 {
+		if _, err := d.uint32(); err != nil {
+			return nil, err
+		}
+	}
+	if geometryType&ewkbZ != 0 {
+		d.dimensions++
+	}
+	if geometryType&ewkbM != 0 {
+		d.dimensions++
+	}
+	geometryType &^= typeFlags
+
+	// ISO WKB encodes Z, M and ZM in the thousands of the type code.
+	switch geometryType / 1000 {
+	case 1, 2:
+		d.dimensions++
+	case 3:
+		d.dimensions += 2
+	}
+	geometryType %= 1000
+
+	if geometryType != want {
+		return nil, fmt.Errorf("wkb: expected geometry type %d, got %d", want, geometryType)
+	}
+
+	return d, nil
+}
+
+func (d *decoder) byteOrder() error {
+	if len(d.data) < 1 {
+		return ErrTruncated
+	}
+
+	switch d.data[0] {
+	case bigEndian:
+		d.order = binary.BigEndian
+	case littleEndian:
+		d.order = binary.LittleEndian
+	default:
+		return fmt.Errorf("wkb: invalid byte order %d", d.data[0])
+	}
+	d.data = d.data[1:]
+
+	return nil
+}
+
+func (d *decoder) sequence() ([]go_huawei.Coordinate, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(n)*uint64(d.dimensions)*8 > uint64(len(d.data)) {
+		return nil, ErrTruncated
+	}
+
+	coords := make([]go_huawei.Coordinate, n)
+	for i := range coords {
+		if coords[i], err = d.position(); err != nil {
+			return nil, err
+		}
+	}
+
+	return coords, nil
+}
+
+func (d *decoder) position() (go_huawei.Coordinate, error) {
+	if len(d.data) < d.dimensions*8 {
+		return go_huawei.Coordinate{}, ErrTruncated
+	}
+
+	c := go_huawei.Coordinate{
+		Lng: math.Float64frombits(d.order.Uint64(d.data[0:8])),
+		Lat: math.Float64frombits(d.order.Uint64(d.data[8:16])),
+	}
+	d.data = d.data[d.dimensions*8:]
+
+	return c, nil
+}
+
+func (d *decoder) uint32() (uint32, error) {
+	if len(d.data) < 4 {
+		return 0, ErrTruncated
+	}
+
+	v := d.order.Uint32(d.data)
+	d.data = d.data[4:]
+
+	return v, nil
+}
@@ -0,0 +1,120 @@
+package wkb
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestEncodePoint(t *testing.T) {
+	c := go_huawei.Coordinate{Lng: 1, Lat: 2}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{"little endian", nil, "0101000000000000000000f03f0000000000000040"},
+		{"big endian", []Option{WithByteOrder(binary.BigEndian)}, "00000000013ff00000000000004000000000000000"},
+		{"EWKB", []Option{WithSRID(4326)}, "0101000020e6100000000000000000f03f0000000000000040"},
+	}
+
+	for _, test := range tests {
+		if got := hex.EncodeToString(EncodePoint(c, test.options...)); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	line := []go_huawei.Coordinate{{Lat: 50.45, Lng: 30.5234}, {Lat: -33.8688, Lng: 151.2093}}
+	for _, options := range [][]Option{nil, {WithByteOrder(binary.BigEndian), WithSRID(3857)}} {
+		got, err := DecodeLineString(EncodeLineString(line, options...))
+		if err != nil || !reflect.DeepEqual(got, line) {
+			t.Errorf("DecodeLineString = %v, %v; want %v", got, err, line)
+		}
+	}
+
+	fiji := go_huawei.NewCoordinateBounds([]go_huawei.Coordinate{{Lat: -19, Lng: 177}, {Lat: -16, Lng: -179}})
+	data := EncodeBounds(fiji)
+	d := &decoder{data: data[9:], order: binary.LittleEndian, dimensions: 2}
+	if ring, err := d.sequence(); err != nil || len(ring) != 5 || ring[1].Lng != 181 {
+		t.Errorf("ring = %v, %v; want an east edge at 181", ring, err)
+	}
+	if b, err := DecodeBounds(data); err != nil || b.Southwest != fiji.Southwest || b.Northeast != fiji.Northeast {
+		t.Errorf("DecodeBounds = %+v, %v; want %+v", b, err, fiji)
+	}
+
+	empty, err := DecodeBounds(EncodeBounds(go_huawei.CoordinateBounds{}))
+	if err != nil || !empty.IsEmpty() {
+		t.Errorf("empty bounds decoded as %+v, %v", empty, err)
+	}
+}
+
+func TestDecodeDimensions(t *testing.T) {
+	want := go_huawei.Coordinate{Lng: 1, Lat: 2}
+
+	for name, s := range map[string]string{
+		"ISO Z":  "01e9030000000000000000f03f00000000000000400000000000002440",
+		"ISO M":  "01d1070000000000000000f03f00000000000000400000000000002440",
+		"ISO ZM": "01b90b0000000000000000f03f000000000000004000000000000024400000000000003440",
+		"EWKB Z": "0101000080000000000000f03f00000000000000400000000000002440",
+	} {
+		if c, err := DecodePoint(mustHex(t, s)); err != nil || c != want {
+			t.Errorf("%s: DecodePoint = %v, %v", name, c, err)
+		}
+	}
+
+	// A Z line string of two positions, the second cut short.
+	data := mustHex(t, "01ea03000002000000"+
+		"000000000000f03f00000000000000400000000000002440"+
+		"000000000000f03f0000000000000040")
+	if _, err := DecodeLineString(data); !errors.Is(err, ErrTruncated) {
+		t.Errorf("truncated Z line string: err = %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	point := EncodePoint(go_huawei.Coordinate{Lng: 1, Lat: 2})
+
+	if _, err := DecodeLineString(point); err == nil || err.Error() != "wkb: expected geometry type 2, got 1" {
+		t.Errorf("wrong type: err = %v", err)
+	}
+	if _, err := DecodePoint([]byte{7, 1, 0, 0, 0}); err == nil || err.Error() != "wkb: invalid byte order 7" {
+		t.Errorf("bad byte order: err = %v", err)
+	}
+	for n := 0; n < len(point); n++ {
+		if _, err := DecodePoint(point[:n]); !errors.Is(err, ErrTruncated) {
+			t.Errorf("DecodePoint of %d bytes: err = %v", n, err)
+		}
+	}
+
+	// A count larger than the data must not allocate it.
+	huge := mustHex(t, "0102000000ffffffff")
+	if _, err := DecodeLineString(huge); !errors.Is(err, ErrTruncated) {
+		t.Errorf("huge count: err = %v", err)
+	}
+}
+
+func TestSRID(t *testing.T) {
+	if srid, ok := SRID(EncodePoint(go_huawei.Coordinate{}, WithSRID(4326), WithByteOrder(binary.BigEndian))); !ok || srid != 4326 {
+		t.Errorf("SRID = %d, %v", srid, ok)
+	}
+	if _, ok := SRID(EncodePoint(go_huawei.Coordinate{})); ok {
+		t.Error("SRID found in plain WKB")
+	}
+}
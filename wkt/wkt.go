@@ -0,0 +1,249 @@
+// Package wkt encodes route geometry as OGC Well-Known Text, and PostGIS EWKT
+// when an SRID is given, and decodes it back. The decoder accepts Z, M and ZM
+// geometries and drops the extra ordinates.
+//
+// Positions are written in x y order, that is longitude then latitude.
+package wkt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/internal/bbox"
+)
+
+// Geometry types supported by the package.
+const (
+	Point      = "POINT"
+	LineString = "LINESTRING"
+	Polygon    = "POLYGON"
+)
+
+// Option is the type of options for the Encode functions.
+type Option func(*encoder)
+
+// WithSRID prefixes the text with "SRID=<srid>;", producing EWKT.
+func WithSRID(srid int) Option {
+	return func(e *encoder) {
+		e.srid = srid
+	}
+}
+
+// WithPrecision limits positions to the given number of decimal places. By
+// default the shortest exact representation is used.
+func WithPrecision(precision int) Option {
+	return func(e *encoder) {
+		e.precision = precision
+	}
+}
+
+type encoder struct {
+	srid      int
+	precision int
+}
+
+func newEncoder(options []Option) *encoder {
+	e := &encoder{precision: -1}
+	for _, option := range options {
+		option(e)
+	}
+
+	return e
+}
+
+// EncodePoint returns c as a POINT.
+func EncodePoint(c go_huawei.Coordinate, options ...Option) string {
+	e := newEncoder(options)
+	return e.prefix() + Point + "(" + e.position(c) + ")"
+}
+
+// EncodeLineString returns coords, such as Path.Overview() or Step.Polyline,
+// as a LINESTRING.
+func EncodeLineString(coords []go_huawei.Coordinate, options ...Option) string {
+	e := newEncoder(options)
+	if len(coords) == 0 {
+		return e.prefix() + LineString + " EMPTY"
+	}
+
+	return e.prefix() + LineString + e.sequence(coords)
+}
+
+// EncodeBounds returns b as a POLYGON with a counter-clockwise ring starting at
+// the south-west corner. Bounds crossing the antimeridian get an east edge
+// beyond 180 so that the polygon covers the right side of the globe.
+func EncodeBounds(b go_huawei.CoordinateBounds, options ...Option) string {
+	e := newEncoder(options)
+	if b.IsEmpty() {
+		return e.prefix() + Polygon + " EMPTY"
+	}
+
+	return e.prefix() + Polygon + "(" + e.sequence(bbox.Ring(b)) + ")"
+}
+
+// DecodePoint parses a POINT.
+func DecodePoint(s string) (go_huawei.Coordinate, error) {
+	coords, err := decode(s, Point)
+	if err != nil {
+		return go_huawei.Coordinate{}, err
+	}
+
+	if len(coords) != 1 {
+		return go_huawei.Coordinate{}, errors.New("wkt: empty point")
+	}
+
+	return coords[0], nil
+}
+
+// DecodeLineString parses a LINESTRING.
+func DecodeLineString(s string) ([]go_huawei.Coordinate, error) {
+	return decode(s, LineString)
+}
+
+// DecodeBounds parses a POLYGON and returns the bounds of its exterior ring.
+// Longitudes beyond 180, as written by EncodeBounds, are wrapped back.
+func DecodeBounds(s string) (go_huawei.CoordinateBounds, error) {
+	ring, err := decode(s, Polygon)
+	if err != nil {
+		return go_huawei.CoordinateBounds{}, err
+	}
+
+	return bbox.FromRing(ring), nil
+}
+
+// SRID returns the SRID of an EWKT string and whether it has one.
+func SRID(s string) (int, bool) {
+	srid, _, err := splitSRID(s)
+	return srid, err == nil && srid != 0
+}
+
+func (e *encoder) prefix() string {
+	if e.srid == 0 {
+		return ""
+	}
+
+	return "SRID=" + strconv.Itoa(e.srid) + ";"
+}
+
+func (e *encoder) sequence(coords []go_huawei.Coordinate) string {
+	positions := make([]string, len(coords))
+	for i, c := range coords {
+		positions[i] = e.position(c)
+	}
+
+	return "(" + strings.Join(positions, ",") + ")"
+}
+
+func (e *encoder) position(c go_huawei.Coordinate) string {
+	return strconv.FormatFloat(c.Lng, 'f', e.precision, 64) + " " + strconv.FormatFloat(c.Lat, 'f', e.precision, 64)
+}
+
+func splitSRID(s string) (int, string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToUpper(s), "SRID=") {
+		return 0, s, nil
+	}
+
+	i := strings.IndexByte(s, ';')
+	if i < 0 {
+		return 0, s, errors.New("wkt: SRID without geometry")
+	}
+
+	srid, err := strconv.Atoi(strings.TrimSpace(s[len("SRID="):i]))
+	if err != nil {
+		return 0, s, fmt.Errorf("wkt: invalid SRID %q", s[len("SRID="):i])
+	}
+
+	return srid, strings.TrimSpace(s[i+1:]), nil
+}
+
+// decode parses a geometry of the given type and returns its positions; for a
+// polygon, those of the exterior ring. Z and M ordinates are dropped.
+func decode(s, geometryType string) ([]go_huawei.Coordinate, error) {
+	_, s, err := splitSRID(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s) < len(geometryType) || !strings.EqualFold(s[:len(geometryType)], geometryType) {
+		return nil, fmt.Errorf("wkt: expected %s", geometryType)
+	}
+
+	body := strings.TrimSpace(s[len(geometryType):])
+	// Without a Z, M or ZM tag a position may still carry extra ordinates.
+	minOrdinates, maxOrdinates := 2, 4
+	if tag, rest := dimensionTag(body); tag != "" {
+		minOrdinates = 2 + len(tag)
+		maxOrdinates = minOrdinates
+		body = rest
+	}
+
+	if strings.EqualFold(body, "EMPTY") {
+		return nil, nil
+	}
+
+	if geometryType == Polygon {
+		body, err = unwrap(body)
+		if err != nil {
+			return nil, err
+		}
+		// Only the exterior ring is needed.
+		if i := strings.IndexByte(body, ')'); i >= 0 {
+			body = body[:i+1]
+		}
+	}
+
+	body, err = unwrap(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var coords []go_huawei.Coordinate
+	for i, position := range strings.Split(body, ",") {
+		fields := strings.Fields(position)
+		if len(fields) < minOrdinates || len(fields) > maxOrdinates {
+			return nil, fmt.Errorf("wkt: invalid position %d %q", i, strings.TrimSpace(position))
+		}
+
+		lng, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("wkt: invalid x in position %d: %q", i, fields[0])
+		}
+
+		lat, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("wkt: invalid y in position %d: %q", i, fields[1])
+		}
+
+		coords = append(coords, go_huawei.Coordinate{Lat: lat, Lng: lng})
+	}
+
+	return coords, nil
+}
+
+// dimensionTag splits the Z, M or ZM tag off the body of a geometry.
+func dimensionTag(body string) (string, string) {
+	for _, tag := range []string{"ZM", "Z", "M"} {
+		if len(body) <= len(tag) || !strings.EqualFold(body[:len(tag)], tag) {
+			continue
+		}
+
+		if next := body[len(tag)]; next == ' ' || next == '(' {
+			return tag, strings.TrimSpace(body[len(tag):])
+		}
+	}
+
+	return "", body
+}
+
+// unwrap strips the outer parentheses of s.
+func unwrap(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return "", fmt.Errorf("wkt: unbalanced parentheses in %q", s)
+	}
+
+	return strings.TrimSpace(s[1 : len(s)-1]), nil
+}
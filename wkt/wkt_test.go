@@ -0,0 +1,112 @@
+package wkt_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stremovskyy/go-huawei"
+	"github.com/stremovskyy/go-huawei/wkt"
+)
+
+func TestEncode(t *testing.T) {
+	line := []go_huawei.Coordinate{{Lat: 50.45, Lng: 30.5234}, {Lat: 50.4547, Lng: 30.5238}}
+	fiji := go_huawei.NewCoordinateBounds([]go_huawei.Coordinate{{Lat: -19, Lng: 177}, {Lat: -16, Lng: -179}})
+
+	tests := []struct {
+		got, want string
+	}{
+		{wkt.EncodePoint(line[0]), "POINT(30.5234 50.45)"},
+		{wkt.EncodePoint(line[0], wkt.WithSRID(4326), wkt.WithPrecision(2)), "SRID=4326;POINT(30.52 50.45)"},
+		{wkt.EncodeLineString(line), "LINESTRING(30.5234 50.45,30.5238 50.4547)"},
+		{wkt.EncodeLineString(nil), "LINESTRING EMPTY"},
+		{wkt.EncodeBounds(fiji), "POLYGON((177 -19,181 -19,181 -16,177 -16,177 -19))"},
+		{wkt.EncodeBounds(go_huawei.CoordinateBounds{}, wkt.WithSRID(4326)), "SRID=4326;POLYGON EMPTY"},
+	}
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("got  %s\nwant %s", test.got, test.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	line := []go_huawei.Coordinate{{Lat: 50.45, Lng: 30.5234}, {Lat: -33.8688, Lng: 151.2093}, {Lat: 0, Lng: -180}}
+	got, err := wkt.DecodeLineString(wkt.EncodeLineString(line, wkt.WithSRID(4326)))
+	if err != nil || !reflect.DeepEqual(got, line) {
+		t.Errorf("DecodeLineString = %v, %v; want %v", got, err, line)
+	}
+
+	for _, b := range []go_huawei.CoordinateBounds{
+		go_huawei.NewCoordinateBounds(line[:2]),
+		go_huawei.NewCoordinateBounds([]go_huawei.Coordinate{{Lat: -19, Lng: 177}, {Lat: -16, Lng: -179}}),
+	} {
+		decoded, err := wkt.DecodeBounds(wkt.EncodeBounds(b))
+		if err != nil || decoded.Southwest != b.Southwest || decoded.Northeast != b.Northeast {
+			t.Errorf("DecodeBounds = %+v, %v; want %+v", decoded, err, b)
+		}
+	}
+}
+
+func TestDecodeDimensions(t *testing.T) {
+	want := []go_huawei.Coordinate{{Lng: 1, Lat: 2}, {Lng: 3, Lat: 4}}
+
+	for _, s := range []string{
+		"LINESTRING Z (1 2 10, 3 4 20)",
+		"LINESTRING M(1 2 0.5,3 4 0.7)",
+		"linestring zm ( 1 2 10 0.5 , 3 4 20 0.7 )",
+		"LINESTRINGZ(1 2 10,3 4 20)",
+		"SRID=4326;LINESTRING Z (1 2 10,3 4 20)",
+		"LINESTRING(1 2 10,3 4 20)",
+	} {
+		got, err := wkt.DecodeLineString(s)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("DecodeLineString(%q) = %v, %v", s, got, err)
+		}
+	}
+
+	if c, err := wkt.DecodePoint("POINT ZM (30.5 50.4 120 7)"); err != nil || c != (go_huawei.Coordinate{Lng: 30.5, Lat: 50.4}) {
+		t.Errorf("DecodePoint = %v, %v", c, err)
+	}
+	if b, err := wkt.DecodeBounds("POLYGON Z ((0 0 1,2 0 1,2 1 1,0 1 1,0 0 1))"); err != nil || b.Northeast != (go_huawei.Coordinate{Lng: 2, Lat: 1}) {
+		t.Errorf("DecodeBounds = %+v, %v", b, err)
+	}
+	if coords, err := wkt.DecodeLineString("LINESTRING Z EMPTY"); err != nil || coords != nil {
+		t.Errorf("DecodeLineString of Z EMPTY = %v, %v", coords, err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		in, err string
+	}{
+		{"POLYGON((0 0,1 1))", "expected LINESTRING"},
+		{"LINESTRING(1 2,3)", `invalid position 1 "3"`},
+		{"LINESTRING Z (1 2,3 4)", `invalid position 0 "1 2"`},
+		{"LINESTRING M (1 2 3 4)", `invalid position 0 "1 2 3 4"`},
+		{"LINESTRING(1 x)", `invalid y in position 0: "x"`},
+		{"LINESTRING(1 2", "unbalanced parentheses"},
+		{"SRID=abc;LINESTRING(1 2)", `invalid SRID "abc"`},
+		{"SRID=4326", "SRID without geometry"},
+	}
+
+	for _, test := range tests {
+		if _, err := wkt.DecodeLineString(test.in); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("DecodeLineString(%q) = %v, want %q", test.in, err, test.err)
+		}
+	}
+
+	if _, err := wkt.DecodePoint("POINT EMPTY"); err == nil {
+		t.Error("DecodePoint of an empty point succeeded")
+	}
+}
+
+func TestSRID(t *testing.T) {
+	if srid, ok := wkt.SRID("SRID=3857;POINT(0 0)"); !ok || srid != 3857 {
+		t.Errorf("SRID = %d, %v", srid, ok)
+	}
+	if _, ok := wkt.SRID("POINT(0 0)"); ok {
+		t.Error("SRID found in plain WKT")
+	}
+}